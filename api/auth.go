@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/SylvanSol/Entain_Test/common/logging"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+// forwardedHeaders are passed to the backends as gRPC metadata of the same name
+// in lower case: the caller's token, so the backends authenticate the original
+// caller, API key, which the backends rate limit by, and request id, which the
+// backends log with.
+var forwardedHeaders = []string{"Authorization", ratelimit.APIKeyHeader, logging.RequestIDHeader}
+
+// headerMatcher forwards forwardedHeaders from the generated gateway handlers.
+// Authorization is forwarded by the gateway runtime already.
+func headerMatcher(key string) (string, bool) {
+	for _, header := range forwardedHeaders {
+		if header != "Authorization" && http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(header) {
+			return strings.ToLower(header), true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher returns the backends' response metadata as
+// Grpc-Metadata-* headers, as the gateway runtime does by default, except the
+// request id, which requestLog already returns as X-Request-Id.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == logging.RequestIDMetadata {
+		return "", false
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// forwardMetadata returns the request's context carrying forwardedHeaders and
+// the client's address, in x-forwarded-for, as gRPC metadata.
+//
+// The generated gateway handlers do this themselves; the hand-written ones,
+// which call the backends directly, use this.
+func forwardMetadata(r *http.Request) context.Context {
+	ctx := r.Context()
+	for _, header := range forwardedHeaders {
+		if value := r.Header.Get(header); value != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(header), value)
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", host)
+	}
+	return ctx
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestForwardMetadata(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/search?q=red", nil)
+	r.RemoteAddr = "203.0.113.9:41000"
+	md, _ := metadata.FromOutgoingContext(forwardMetadata(r))
+	assert.Empty(t, md.Get("authorization"), "anonymous requests carry no token")
+	assert.Equal(t, []string{"203.0.113.9"}, md.Get("x-forwarded-for"))
+
+	r.Header.Set("Authorization", "Bearer abc.def.ghi")
+	r.Header.Set("X-API-Key", "k1")
+	md, _ = metadata.FromOutgoingContext(forwardMetadata(r))
+	assert.Equal(t, []string{"Bearer abc.def.ghi"}, md.Get("authorization"))
+	assert.Equal(t, []string{"k1"}, md.Get("x-api-key"))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+)
+
+// Config is the api gateway's configuration. It is loaded from, in increasing
+// order of precedence, defaultConfig, the YAML file named by -config or
+// API_CONFIG, API_* environment variables and flags.
+type Config struct {
+	Listen   string       `yaml:"listen" flag:"api-endpoint" usage:"API endpoint"`
+	Backends backends     `yaml:"backends"`
+	TLS      backendTLS   `yaml:"backend_tls"`
+	HTTP     httpSettings `yaml:"http"`
+	// RateLimit applies per client to each HTTP path; Routes is keyed by path,
+	// e.g. "/v1/search".
+	RateLimit config.RateLimit `yaml:"ratelimit"`
+	Tracing   config.Tracing   `yaml:"tracing"`
+	Logging   config.Logging   `yaml:"logging"`
+	Timeouts  config.Timeouts  `yaml:"timeouts"`
+}
+
+// backends are the gRPC services the gateway fronts.
+type backends struct {
+	Racing string `yaml:"racing" flag:"grpc-endpoint" usage:"gRPC server endpoint"`
+	Sports string `yaml:"sports" flag:"sports-endpoint" usage:"Sports gRPC server endpoint"`
+}
+
+// backendTLS is how the gateway dials the backends over TLS. CertFile and
+// KeyFile are the gateway's client certificate, for backends that require
+// mutual TLS.
+type backendTLS struct {
+	Enabled    bool   `yaml:"enabled" flag:"backend-tls" usage:"dial the backends over TLS"`
+	CAFile     string `yaml:"ca_file" flag:"backend-tls-ca" usage:"PEM CA bundle to verify the backends with; empty uses the system roots"`
+	CertFile   string `yaml:"cert_file" flag:"backend-tls-cert" usage:"PEM client certificate to present to the backends"`
+	KeyFile    string `yaml:"key_file" flag:"backend-tls-key" usage:"PEM private key for the client certificate"`
+	ServerName string `yaml:"server_name" flag:"backend-tls-server-name" usage:"name to verify the backends' certificates against; empty uses the dialled host"`
+}
+
+func (t *backendTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("backend_tls.cert_file and backend_tls.key_file must be set together")
+	}
+	if !t.Enabled && (t.CAFile != "" || t.CertFile != "") {
+		return errors.New("backend_tls files are set but backend_tls.enabled is false")
+	}
+	return nil
+}
+
+// httpSettings bound how long a client may take over each request, and how long
+// it may cache the responses it gets.
+type httpSettings struct {
+	ReadTimeout  time.Duration `yaml:"read_timeout" flag:"http-read-timeout" usage:"maximum time to read a request, including its body"`
+	WriteTimeout time.Duration `yaml:"write_timeout" flag:"http-write-timeout" usage:"maximum time to write a response"`
+	// CacheMaxAge caps the max-age of cacheable responses, which is otherwise
+	// the time until a race in the response next changes status.
+	CacheMaxAge time.Duration `yaml:"cache_max_age" flag:"http-cache-max-age" usage:"longest clients and CDNs may cache GET /v1/races responses"`
+	// DrainDelay is how long the gateway keeps serving, with /readyz failing,
+	// after it is asked to stop, so load balancers stop sending it traffic
+	// before it closes its listener.
+	DrainDelay time.Duration `yaml:"drain_delay" flag:"http-drain-delay" usage:"how long /readyz fails before the gateway stops accepting connections on shutdown"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Listen: "localhost:8000",
+		Backends: backends{
+			Racing: "localhost:9000",
+			Sports: "localhost:9100",
+		},
+		HTTP: httpSettings{
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			CacheMaxAge:  30 * time.Second,
+			DrainDelay:   5 * time.Second,
+		},
+		RateLimit: config.RateLimit{
+			Rate:  20,
+			Burst: 40,
+		},
+		Tracing: config.Tracing{
+			Exporter: tracing.ExporterNone,
+			Endpoint: "http://localhost:4317",
+		},
+		Logging: config.Logging{
+			Level: "info",
+		},
+		Timeouts: config.Timeouts{
+			Shutdown: 10 * time.Second,
+		},
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.HTTP.ReadTimeout <= 0 || c.HTTP.WriteTimeout <= 0 {
+		return errors.New("http.read_timeout and http.write_timeout must be positive")
+	}
+	if c.HTTP.CacheMaxAge < 0 || c.HTTP.DrainDelay < 0 {
+		return errors.New("http.cache_max_age and http.drain_delay must not be negative")
+	}
+	return errors.Join(
+		config.ValidateAddress("listen", c.Listen),
+		config.ValidateAddress("backends.racing", c.Backends.Racing),
+		config.ValidateAddress("backends.sports", c.Backends.Sports),
+	)
+}
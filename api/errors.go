@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorResponse is the JSON body returned for every failed gateway request:
+//
+//	{
+//	  "error": {
+//	    "code": 400,
+//	    "status": "INVALID_ARGUMENT",
+//	    "message": "invalid sort.field: ...",
+//	    "field_violations": [{"field": "sort.field", "description": "..."}],
+//	    "resource": {"type": "race", "name": "42"},
+//	    "precondition_failures": [{"type": "ABANDONED", "subject": "race 42", "description": "..."}],
+//	    "required_roles": ["trader"],
+//	    "correlation_id": "9f2c4e1a7b3d5f60"
+//	  }
+//	}
+//
+// Only the fields relevant to the failure are present.
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	// Code is the HTTP status code.
+	Code int `json:"code"`
+	// Status is the gRPC status code name.
+	Status               string                `json:"status"`
+	Message              string                `json:"message"`
+	FieldViolations      []fieldViolation      `json:"field_violations,omitempty"`
+	Resource             *resourceInfo         `json:"resource,omitempty"`
+	PreconditionFailures []preconditionFailure `json:"precondition_failures,omitempty"`
+	// RequiredRoles lists the roles that would allow a denied call.
+	RequiredRoles []string `json:"required_roles,omitempty"`
+	// CorrelationID identifies the backend log line for an internal error.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type resourceInfo struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type preconditionFailure struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// errorHandler translates a gRPC status, including its google.rpc error
+// details, into an errorResponse.
+func errorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+	httpStatus := runtime.HTTPStatusFromCode(st.Code())
+
+	body := errorBody{
+		Code:    httpStatus,
+		Status:  code.Code(st.Code()).String(),
+		Message: st.Message(),
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				body.FieldViolations = append(body.FieldViolations, fieldViolation{Field: v.Field, Description: v.Description})
+			}
+		case *errdetails.ResourceInfo:
+			body.Resource = &resourceInfo{Type: d.ResourceType, Name: d.ResourceName}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.Violations {
+				body.PreconditionFailures = append(body.PreconditionFailures, preconditionFailure{Type: v.Type, Subject: v.Subject, Description: v.Description})
+			}
+		case *errdetails.RequestInfo:
+			body.CorrelationID = d.RequestId
+		case *errdetails.RetryInfo:
+			w.Header().Set("Retry-After", ceilSeconds(d.RetryDelay.AsDuration()))
+		case *errdetails.ErrorInfo:
+			if roles := d.Metadata["required_roles"]; roles != "" {
+				body.RequiredRoles = strings.Split(roles, ",")
+			}
+		}
+	}
+
+	if st.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: body})
+}
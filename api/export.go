@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	exportFormatICS = "ics"
+	exportFormatCSV = "csv"
+)
+
+// csvDefaultColumns are the columns of a CSV export that does not choose its own.
+var csvDefaultColumns = []string{"id", "meeting_id", "name", "number", "visible", "advertised_start_time", "status"}
+
+// exportParams are the export's own query parameters, which are not part of
+// the ListRaces request.
+var exportParams = utilities.NewDoubleArray([][]string{{"format"}, {"columns"}})
+
+// icsFields are the race fields a calendar export needs.
+var icsFields = []string{"id", "meeting_id", "name", "number", "advertised_start_time", "status"}
+
+// exportPageSize is how many races an export asks ListRaces for at a time.
+const exportPageSize = 500
+
+// exportHandler serves GET /v1/races/export, rendering the races GET /v1/races
+// would list as an iCalendar feed or a CSV file. It takes the same filter and
+// sort parameters, plus:
+//
+//   - format, "ics" or "csv". Without it the Accept header decides, naming
+//     text/calendar or text/csv, and CSV is the default.
+//   - columns, for CSV, a comma-separated list of race fields by proto or JSON
+//     name. It defaults to every field.
+//
+// Races are fetched from ListRaces a page at a time, and each page is written
+// to the client before the next is fetched, so only one page is ever held in
+// memory. Each page also pushes the write deadline writeTimeout further out,
+// so a long export is not cut off as long as it keeps moving.
+func exportHandler(mux *runtime.ServeMux, racingClient racing.RacingClient, writeTimeout time.Duration) runtime.HandlerFunc {
+	race := (&racing.Race{}).ProtoReflect().Descriptor()
+
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := forwardMetadata(r)
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+		query := r.URL.Query()
+
+		format, ok := exportFormat(r)
+		if !ok {
+			runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+				Field:       "format",
+				Description: "must be ics or csv",
+			}))
+			return
+		}
+
+		names := icsFields
+		if format == exportFormatCSV {
+			names = csvDefaultColumns
+			if raw := strings.Join(query["columns"], ","); raw != "" {
+				names = strings.Split(raw, ",")
+			}
+		}
+		columns := make([]protoreflect.FieldDescriptor, 0, len(names))
+		mask := &fieldmaskpb.FieldMask{}
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			field := race.Fields().ByName(protoreflect.Name(name))
+			if field == nil {
+				field = race.Fields().ByJSONName(name)
+			}
+			if field == nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+					Field:       "columns",
+					Description: fmt.Sprintf("unknown race field %q", name),
+				}))
+				return
+			}
+			columns = append(columns, field)
+			mask.Paths = append(mask.Paths, string(field.Name()))
+		}
+
+		req := &racing.ListRacesRequest{}
+		if err := runtime.PopulateQueryParameters(req, query, exportParams); err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		req.ReadMask, req.IncludeFacets = mask, false
+		req.PageSize, req.PageToken = exportPageSize, ""
+
+		// The first page is fetched before anything is written, so an error
+		// from ListRaces can still be sent as the usual JSON error.
+		resp, err := racingClient.ListRaces(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		buf := bufio.NewWriter(w)
+		var out raceExport
+		switch format {
+		case exportFormatICS:
+			w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="races.ics"`)
+			out = &icsExport{ics: icsWriter{buf}, now: time.Now()}
+		case exportFormatCSV:
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="races.csv"`)
+			out = &csvExport{out: csv.NewWriter(buf), columns: columns}
+		}
+
+		deadline := http.NewResponseController(w)
+		out.begin()
+		for {
+			out.write(resp.Races)
+			if resp.NextPageToken == "" {
+				break
+			}
+			if err := buf.Flush(); err != nil {
+				// The client has gone.
+				return
+			}
+			_ = deadline.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+			req.PageToken = resp.NextPageToken
+			if resp, err = racingClient.ListRaces(ctx, req); err != nil {
+				// Part of the file has been sent with a 200, so the error
+				// cannot be reported. Abort the response instead, so the
+				// client sees it break off rather than a short file that
+				// looks whole.
+				slog.ErrorContext(ctx, "race export failed part way", "error", err)
+				panic(http.ErrAbortHandler)
+			}
+		}
+		out.end()
+		_ = buf.Flush()
+	}
+}
+
+// exportFormat picks the export format from ?format=, or else from the first
+// of text/calendar and text/csv the Accept header names. It reports false for
+// an unknown ?format=.
+func exportFormat(r *http.Request) (string, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format, format == exportFormatICS || format == exportFormatCSV
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/calendar":
+			return exportFormatICS, true
+		case "text/csv":
+			return exportFormatCSV, true
+		}
+	}
+	return exportFormatCSV, true
+}
+
+// raceExport renders races in an export format: begin, then write for each
+// page of races, then end.
+type raceExport interface {
+	begin()
+	write(races []*racing.Race)
+	end()
+}
+
+// csvExport writes a header row of column names, then a row per race.
+type csvExport struct {
+	out     *csv.Writer
+	columns []protoreflect.FieldDescriptor
+}
+
+func (e *csvExport) begin() {
+	row := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		row[i] = string(column.Name())
+	}
+	_ = e.out.Write(row)
+}
+
+func (e *csvExport) write(races []*racing.Race) {
+	row := make([]string, len(e.columns))
+	for _, race := range races {
+		m := race.ProtoReflect()
+		for i, column := range e.columns {
+			row[i] = csvValue(column, m.Get(column))
+		}
+		_ = e.out.Write(row)
+	}
+	e.out.Flush()
+}
+
+func (e *csvExport) end() {}
+
+// csvValue renders a race field for a CSV cell: enums by name, timestamps in
+// RFC 3339, text through csvText and everything else as its plain value.
+func csvValue(field protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return csvText(v.String())
+	case protoreflect.EnumKind:
+		if value := field.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.MessageKind:
+		if ts, ok := v.Message().Interface().(*timestamppb.Timestamp); ok && ts.IsValid() {
+			return ts.AsTime().UTC().Format(time.RFC3339)
+		}
+		return ""
+	default:
+		return v.String()
+	}
+}
+
+// csvText escapes text that a spreadsheet would read as a formula, prefixing it
+// with a quote. Names come from provider feeds, so they cannot be trusted not
+// to start with one.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// icsExport writes races as an iCalendar (RFC 5545) feed with a VEVENT per
+// race. Abandoned races stay in the feed as cancelled events, so calendars that
+// imported them before drop them.
+type icsExport struct {
+	ics icsWriter
+	now time.Time
+}
+
+func (e *icsExport) begin() {
+	e.ics.line("BEGIN", "VCALENDAR")
+	e.ics.line("VERSION", "2.0")
+	e.ics.line("PRODID", "-//Entain//Race schedule//EN")
+	e.ics.line("CALSCALE", "GREGORIAN")
+	e.ics.line("METHOD", "PUBLISH")
+}
+
+func (e *icsExport) write(races []*racing.Race) {
+	const stamp = "20060102T150405Z"
+	for _, race := range races {
+		eventStatus := "CONFIRMED"
+		if race.Status == racing.RaceStatus_ABANDONED {
+			eventStatus = "CANCELLED"
+		}
+
+		e.ics.line("BEGIN", "VEVENT")
+		e.ics.line("UID", "race-"+strconv.FormatInt(race.Id, 10)+"@entain")
+		e.ics.line("DTSTAMP", e.now.UTC().Format(stamp))
+		e.ics.line("DTSTART", race.AdvertisedStartTime.AsTime().UTC().Format(stamp))
+		e.ics.line("SUMMARY", icsText(race.Name))
+		e.ics.line("DESCRIPTION", icsText(fmt.Sprintf("Meeting %d, race %d. Status: %s.", race.MeetingId, race.Number, race.Status)))
+		e.ics.line("CATEGORIES", icsText("Meeting "+strconv.FormatInt(race.MeetingId, 10)))
+		e.ics.line("STATUS", eventStatus)
+		e.ics.line("END", "VEVENT")
+	}
+}
+
+func (e *icsExport) end() {
+	e.ics.line("END", "VCALENDAR")
+}
+
+// icsWriter writes iCalendar content lines, ending them with CRLF and folding
+// them at 75 octets as RFC 5545 requires.
+type icsWriter struct {
+	w *bufio.Writer
+}
+
+func (c icsWriter) line(name, value string) {
+	line, limit := name+":"+value, 75
+	for len(line) > limit {
+		// Fold between characters, never inside one.
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = c.w.WriteString(line[:cut] + "\r\n ")
+		// The space starting a continuation line counts towards its length.
+		line, limit = line[cut:], 74
+	}
+	_, _ = c.w.WriteString(line + "\r\n")
+}
+
+// icsText escapes s for an iCalendar TEXT value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newExportTestMux(t *testing.T, client racing.RacingClient) *runtime.ServeMux {
+	mux := runtime.NewServeMux(runtime.WithErrorHandler(errorHandler))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/races/export", exportHandler(mux, client, time.Minute)))
+	return mux
+}
+
+func exportRaces() []*racing.Race {
+	start := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	return []*racing.Race{
+		{Id: 1, MeetingId: 5, Name: "Cup, \"Open\"", Number: 2, Visible: true, AdvertisedStartTime: timestamppb.New(start), Status: racing.RaceStatus_OPEN},
+		{Id: 2, MeetingId: 6, Name: "Plate", Number: 3, AdvertisedStartTime: timestamppb.New(start.Add(time.Hour)), Status: racing.RaceStatus_ABANDONED},
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	client := &recordingRacing{fakeRacing: fakeRacing{resp: &racing.ListRacesResponse{Races: exportRaces()}}}
+	mux := newExportTestMux(t, client)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races/export?filter.meeting_ids=5&filter.only_visible=true&sort.field=name&include_facets=true", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="races.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id,meeting_id,name,number,visible,advertised_start_time,status\n"+
+		"1,5,\"Cup, \"\"Open\"\"\",2,true,2025-01-01T10:30:00Z,OPEN\n"+
+		"2,6,Plate,3,false,2025-01-01T11:30:00Z,ABANDONED\n", rec.Body.String())
+
+	// The filter and sort are those of GET /v1/races.
+	assert.Equal(t, []int64{5}, client.req.GetFilter().GetMeetingIds())
+	assert.True(t, client.req.GetFilter().GetOnlyVisible())
+	assert.Equal(t, "name", client.req.GetSort().GetField())
+	assert.False(t, client.req.GetIncludeFacets())
+
+	// Chosen columns, by either name, are all that is asked of the backend.
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races/export?format=csv&columns=name,advertisedStartTime", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"name", "advertised_start_time"}, client.req.GetReadMask().GetPaths())
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "name,advertised_start_time\n\"Cup, \"\"Open\"\"\",2025-01-01T10:30:00Z\n"), rec.Body.String())
+}
+
+func TestExport_CSVFormulas(t *testing.T) {
+	start := timestamppb.New(time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC))
+	var races []*racing.Race
+	for i, name := range []string{`=HYPERLINK("http://evil.example","Cup")`, "+1 Plate", "-Sprint", "@SUM(A1)", "\tTab", "\rReturn", "Derby - Open"} {
+		races = append(races, &racing.Race{Id: int64(i + 1), Name: name, AdvertisedStartTime: start})
+	}
+	mux := newExportTestMux(t, &fakeRacing{resp: &racing.ListRacesResponse{Races: races}})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races/export?format=csv&columns=name", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "name\n"+
+		"\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"Cup\"\")\"\n"+
+		"'+1 Plate\n"+
+		"'-Sprint\n"+
+		"'@SUM(A1)\n"+
+		"'\tTab\n"+
+		"\"'\rReturn\"\n"+
+		"Derby - Open\n", rec.Body.String())
+}
+
+func TestExport_ICS(t *testing.T) {
+	races := exportRaces()
+	races[1].Name = strings.Repeat("Ω", 60)
+	client := &recordingRacing{fakeRacing: fakeRacing{resp: &racing.ListRacesResponse{Races: races}}}
+	mux := newExportTestMux(t, client)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/races/export?filter.only_visible=true", nil)
+	req.Header.Set("Accept", "text/calendar, text/csv;q=0.5")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.ElementsMatch(t, icsFields, client.req.GetReadMask().GetPaths())
+
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, body, "UID:race-1@entain\r\nDTSTAMP:")
+	assert.Contains(t, body, "DTSTART:20250101T103000Z\r\nSUMMARY:Cup\\, \"Open\"\r\nDESCRIPTION:Meeting 5\\, race 2. Status: OPEN.\r\nCATEGORIES:Meeting 5\r\nSTATUS:CONFIRMED\r\n")
+	assert.Contains(t, body, "STATUS:CANCELLED\r\n", "abandoned races are cancelled")
+
+	// Long lines are folded at 75 octets without splitting a character, and
+	// unfold to the original.
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	var unfolded []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		assert.LessOrEqual(t, len(line), 75)
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+	assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("Ω", 60))
+}
+
+func TestExport_Errors(t *testing.T) {
+	client := &recordingRacing{fakeRacing: fakeRacing{resp: &racing.ListRacesResponse{}}}
+	mux := newExportTestMux(t, client)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "unknown format", query: "format=pdf", want: http.StatusBadRequest},
+		{name: "unknown column", query: "columns=id,runners", want: http.StatusBadRequest},
+		{name: "bad filter", query: "filter.only_visible=maybe", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races/export?"+tt.query, nil))
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		})
+	}
+
+	client.err = status.Error(codes.PermissionDenied, "hidden races need the internal role")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races/export", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "BEGIN:VCALENDAR")
+}
+
+// pagedRacing answers ListRaces a page at a time from races, taking delay
+// over each page and failing with err from page failFrom on.
+type pagedRacing struct {
+	racing.RacingClient
+	races    []*racing.Race
+	delay    time.Duration
+	failFrom int
+	err      error
+	sizes    []int32
+}
+
+func (f *pagedRacing) ListRaces(_ context.Context, req *racing.ListRacesRequest, _ ...grpc.CallOption) (*racing.ListRacesResponse, error) {
+	time.Sleep(f.delay)
+	f.sizes = append(f.sizes, req.PageSize)
+	if f.failFrom > 0 && len(f.sizes) >= f.failFrom {
+		return nil, f.err
+	}
+
+	offset, _ := strconv.Atoi(req.PageToken)
+	end := min(offset+int(req.PageSize), len(f.races))
+	resp := &racing.ListRacesResponse{Races: f.races[offset:end]}
+	if end < len(f.races) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+// manyRaces returns n races with ids from 1.
+func manyRaces(n int) []*racing.Race {
+	races := make([]*racing.Race, n)
+	for i := range races {
+		races[i] = &racing.Race{Id: int64(i + 1), Name: "Race", AdvertisedStartTime: timestamppb.Now()}
+	}
+	return races
+}
+
+func TestExport_Pages(t *testing.T) {
+	n := 2*exportPageSize + 1
+	client := &pagedRacing{races: manyRaces(n), delay: 100 * time.Millisecond}
+
+	// Each page pushes the write deadline out, so an export taking longer
+	// than the write timeout still completes.
+	srv := httptest.NewUnstartedServer(newExportTestMux(t, client))
+	srv.Config.WriteTimeout = 150 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/races/export?columns=id")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	assert.Len(t, lines, n+1, "a header and every race")
+	assert.Equal(t, strconv.Itoa(n), lines[n])
+	assert.Equal(t, []int32{exportPageSize, exportPageSize, exportPageSize}, client.sizes)
+}
+
+func TestExport_FailsPartWay(t *testing.T) {
+	client := &pagedRacing{races: manyRaces(2 * exportPageSize), failFrom: 2, err: status.Error(codes.Unavailable, "racing is down")}
+	srv := httptest.NewServer(newExportTestMux(t, client))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/races/export?format=ics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The response breaks off rather than ending like a whole calendar.
+	body, err := io.ReadAll(resp.Body)
+	assert.Error(t, err)
+	assert.NotContains(t, string(body), "END:VCALENDAR")
+}
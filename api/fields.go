@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maskedMIME selects maskedMarshaler for a request. It never reaches clients:
+// the marshaler still answers with application/json.
+const maskedMIME = "application/x-entain-masked+json"
+
+// maskedMarshaler writes responses to requests with ?fields=. Unlike the
+// gateway's default it leaves out unpopulated fields, so fields that were not
+// asked for are not sent back as zero values.
+var maskedMarshaler = &runtime.JSONPb{
+	UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+}
+
+// fieldsRoutes are the GET routes that take a ?fields= parameter, with the
+// message its field names refer to.
+var fieldsRoutes = map[string]protoreflect.MessageDescriptor{
+	"/v1/races":          (&racing.Race{}).ProtoReflect().Descriptor(),
+	"/v1/races:batchGet": (&racing.Race{}).ProtoReflect().Descriptor(),
+}
+
+// readMaskFields lets clients of fieldsRoutes ask for only some fields with
+// ?fields=id,name,advertisedStartTime. It rewrites the parameter into the
+// read_mask the generated handlers parse, turning the JSON names the gateway
+// responds with into the proto names a read mask holds. Names that match no
+// field are passed on as they are, for the backend to reject. The response is
+// written by maskedMarshaler.
+func readMaskFields(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		pattern, ok := runtime.HTTPPattern(r.Context())
+		if !ok || r.URL.Query()["fields"] == nil {
+			next(w, r, pathParams)
+			return
+		}
+		message, ok := fieldsRoutes[pattern.String()]
+		if !ok {
+			next(w, r, pathParams)
+			return
+		}
+
+		query := r.URL.Query()
+		var paths []string
+		for _, value := range append(query["read_mask"], query["fields"]...) {
+			for _, path := range strings.Split(value, ",") {
+				path = strings.TrimSpace(path)
+				if path == "" {
+					continue
+				}
+				if field := message.Fields().ByJSONName(path); field != nil {
+					path = string(field.Name())
+				}
+				paths = append(paths, path)
+			}
+		}
+		query.Del("fields")
+		query.Del("read_mask")
+		if len(paths) > 0 {
+			query.Set("read_mask", strings.Join(paths, ","))
+		}
+
+		r = r.Clone(r.Context())
+		r.URL.RawQuery = query.Encode()
+		r.Header.Set("Accept", maskedMIME)
+		next(w, r, pathParams)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	racinggw "git.neds.sh/matty/entain/api/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMaskFields(t *testing.T) {
+	client := &recordingRacing{fakeRacing: fakeRacing{resp: &racing.ListRacesResponse{}}}
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(readMaskFields))
+	require.NoError(t, racinggw.RegisterRacingHandlerClient(context.Background(), mux, client))
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "json names", query: "fields=id,name,advertisedStartTime", want: []string{"id", "name", "advertised_start_time"}},
+		{name: "proto names", query: "fields=meeting_id", want: []string{"meeting_id"}},
+		{name: "repeated and spaced", query: "fields=id,%20status&fields=number", want: []string{"id", "status", "number"}},
+		{name: "with read_mask", query: "read_mask=visible&fields=name", want: []string{"visible", "name"}},
+		{name: "unknown names passed on", query: "fields=runners", want: []string{"runners"}},
+		{name: "empty", query: "fields=", want: nil},
+		{name: "read_mask alone", query: "read_mask=id", want: []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races?filter.only_visible=true&"+tt.query, nil))
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.want, client.req.GetReadMask().GetPaths())
+			assert.True(t, client.req.GetFilter().GetOnlyVisible(), "other parameters are kept")
+		})
+	}
+}
+
+func TestReadMaskFields_Response(t *testing.T) {
+	// The backend has already left out the fields that were not asked for.
+	client := &fakeRacing{resp: &racing.ListRacesResponse{Races: []*racing.Race{{Id: 1, Name: "Cup"}}}}
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(maskedMIME, maskedMarshaler),
+		runtime.WithMiddlewares(readMaskFields),
+	)
+	require.NoError(t, racinggw.RegisterRacingHandlerClient(context.Background(), mux, client))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races?fields=id,name", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"races": [{"id": "1", "name": "Cup"}]}`, rec.Body.String())
+
+	// Without fields every field is written, as before.
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races", nil))
+	assert.Contains(t, rec.Body.String(), `"visible":false`)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// readinessTimeout bounds each backend health check made by /readyz.
+const readinessTimeout = time.Second
+
+// healthResponse is the body of GET /healthz and GET /readyz.
+type healthResponse struct {
+	// Status is SERVING or NOT_SERVING.
+	Status string `json:"status"`
+	// Backends maps each backend to the status it reported, or UNKNOWN when it
+	// could not be reached. Only /readyz reports backends.
+	Backends map[string]string `json:"backends,omitempty"`
+}
+
+// healthzHandler serves GET /healthz, the gateway's liveness probe. It does not
+// call the backends, so an outage behind the gateway never gets the gateway
+// itself restarted.
+func healthzHandler() runtime.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		writeHealth(w, http.StatusOK, healthResponse{Status: healthpb.HealthCheckResponse_SERVING.String()})
+	}
+}
+
+// readyzHandler serves GET /readyz, the gateway's readiness probe. It asks each
+// backend's grpc.health.v1 service for its overall status, concurrently, and is
+// ready only when every backend reports SERVING. Once stopping is done the
+// gateway is shutting down and is never ready, whatever the backends say.
+func readyzHandler(stopping context.Context, backends map[string]healthpb.HealthClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		if stopping.Err() != nil {
+			writeHealth(w, http.StatusServiceUnavailable, healthResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING.String()})
+			return
+		}
+
+		resp := readiness(r.Context(), backends)
+
+		code := http.StatusOK
+		if resp.Status != healthpb.HealthCheckResponse_SERVING.String() {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealth(w, code, resp)
+	}
+}
+
+// readiness checks every backend and aggregates the result.
+func readiness(ctx context.Context, backends map[string]healthpb.HealthClient) healthResponse {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		resp = healthResponse{
+			Status:   healthpb.HealthCheckResponse_SERVING.String(),
+			Backends: map[string]string{},
+		}
+	)
+
+	for name, client := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+			defer cancel()
+
+			status := healthpb.HealthCheckResponse_UNKNOWN
+			if res, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err == nil {
+				status = res.Status
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Backends[name] = status.String()
+			if status != healthpb.HealthCheckResponse_SERVING {
+				resp.Status = healthpb.HealthCheckResponse_NOT_SERVING.String()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return resp
+}
+
+func writeHealth(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// fakeHealth answers Check with a fixed status or error.
+type fakeHealth struct {
+	healthpb.HealthClient
+	status healthpb.HealthCheckResponse_ServingStatus
+	err    error
+}
+
+func (f *fakeHealth) Check(context.Context, *healthpb.HealthCheckRequest, ...grpc.CallOption) (*healthpb.HealthCheckResponse, error) {
+	return &healthpb.HealthCheckResponse{Status: f.status}, f.err
+}
+
+func TestReadyz(t *testing.T) {
+	serving := &fakeHealth{status: healthpb.HealthCheckResponse_SERVING}
+
+	tests := []struct {
+		name         string
+		sports       healthpb.HealthClient
+		wantCode     int
+		wantBackends map[string]string
+	}{
+		{
+			name:         "all serving",
+			sports:       serving,
+			wantCode:     http.StatusOK,
+			wantBackends: map[string]string{"racing": "SERVING", "sports": "SERVING"},
+		},
+		{
+			name:         "backend not serving",
+			sports:       &fakeHealth{status: healthpb.HealthCheckResponse_NOT_SERVING},
+			wantCode:     http.StatusServiceUnavailable,
+			wantBackends: map[string]string{"racing": "SERVING", "sports": "NOT_SERVING"},
+		},
+		{
+			name:         "backend unreachable",
+			sports:       &fakeHealth{err: status.Error(codes.Unavailable, "connection refused")},
+			wantCode:     http.StatusServiceUnavailable,
+			wantBackends: map[string]string{"racing": "SERVING", "sports": "UNKNOWN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := readyzHandler(context.Background(), map[string]healthpb.HealthClient{"racing": serving, "sports": tt.sports})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+
+			var resp healthResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantBackends, resp.Backends)
+		})
+	}
+}
+
+func TestReadyz_ShuttingDown(t *testing.T) {
+	stopping, stop := context.WithCancel(context.Background())
+	serving := &fakeHealth{status: healthpb.HealthCheckResponse_SERVING}
+	handler := readyzHandler(stopping, map[string]healthpb.HealthClient{"racing": serving, "sports": serving})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Once shutdown starts the gateway is not ready, even with healthy backends.
+	stop()
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var resp healthResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "NOT_SERVING", resp.Status)
+	assert.Empty(t, resp.Backends)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+)
+
+// cachedRoutes are the GET routes whose responses carry ETag and Cache-Control
+// headers, so that clients and CDNs can cache and revalidate them.
+var cachedRoutes = []string{"/v1/races", "/v1/races:batchGet"}
+
+// httpCache adds caching headers to the responses of cachedRoutes and answers
+// conditional requests for them.
+//
+// The ETag is a hash of the response body. The max-age is the time until a race
+// in the response next changes status, which happens at its advertised start
+// time, capped at maxAge.
+type httpCache struct {
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func newHTTPCache(maxAge time.Duration) *httpCache {
+	return &httpCache{maxAge: maxAge, now: time.Now}
+}
+
+type freshnessKey struct{}
+
+// freshness carries when a response goes stale from forwardResponse, which sees
+// the response message, back to middleware, which sets the headers.
+type freshness struct {
+	until time.Time
+}
+
+// middleware buffers the responses of cachedRoutes, so that it can hash them,
+// and replies 304 Not Modified when the request's If-None-Match names the
+// response's ETag. Only successful responses are given caching headers.
+func (c *httpCache) middleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		pattern, ok := runtime.HTTPPattern(r.Context())
+		if r.Method != http.MethodGet || !ok || !slices.Contains(cachedRoutes, pattern.String()) {
+			next(w, r, pathParams)
+			return
+		}
+
+		fresh := &freshness{until: c.now().Add(c.maxAge)}
+		rec := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		next(rec, r.WithContext(context.WithValue(r.Context(), freshnessKey{}, fresh)), pathParams)
+
+		if rec.status != http.StatusOK {
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+			return
+		}
+
+		etag := contentETag(rec.body.Bytes())
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", c.cacheControl(r, fresh.until))
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(rec.body.Bytes())
+	}
+}
+
+// forwardResponse is a runtime.ForwardResponseOption that shortens the
+// freshness of a response listing races to their earliest future advertised
+// start time.
+func (c *httpCache) forwardResponse(ctx context.Context, _ http.ResponseWriter, msg proto.Message) error {
+	fresh, ok := ctx.Value(freshnessKey{}).(*freshness)
+	if !ok {
+		return nil
+	}
+
+	resp, ok := msg.(interface{ GetRaces() []*racing.Race })
+	if !ok {
+		return nil
+	}
+
+	now := c.now()
+	for _, race := range resp.GetRaces() {
+		if start := race.GetAdvertisedStartTime().AsTime(); start.After(now) && start.Before(fresh.until) {
+			fresh.until = start
+		}
+	}
+	return nil
+}
+
+// cacheControl builds the Cache-Control header for a response to r that stays
+// correct until until. Responses to authenticated requests are private, since
+// a CDN must not serve a list of hidden races to someone else.
+func (c *httpCache) cacheControl(r *http.Request, until time.Time) string {
+	maxAge := int64(until.Sub(c.now()) / time.Second)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	scope := "public"
+	if r.Header.Get("Authorization") != "" {
+		scope = "private"
+	}
+	return scope + ", max-age=" + strconv.FormatInt(maxAge, 10)
+}
+
+// contentETag returns a strong ETag for body.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value names etag. As
+// RFC 9110 requires for If-None-Match, weak tags match their strong form.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse holds a response's status and body until the middleware has
+// seen all of it. Headers go straight to the underlying writer's header map.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
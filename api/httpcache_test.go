@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	racinggw "git.neds.sh/matty/entain/api/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordingRacing is a fakeRacing that remembers the last ListRaces request.
+type recordingRacing struct {
+	fakeRacing
+	req *racing.ListRacesRequest
+}
+
+func (f *recordingRacing) ListRaces(ctx context.Context, req *racing.ListRacesRequest, opts ...grpc.CallOption) (*racing.ListRacesResponse, error) {
+	f.req = req
+	return f.fakeRacing.ListRaces(ctx, req, opts...)
+}
+
+func newCacheTestMux(t *testing.T, client racing.RacingClient, now time.Time) *runtime.ServeMux {
+	cache := newHTTPCache(30 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithForwardResponseOption(cache.forwardResponse),
+		runtime.WithMiddlewares(cache.middleware),
+	)
+	require.NoError(t, racinggw.RegisterRacingHandlerClient(context.Background(), mux, client))
+	return mux
+}
+
+func TestHTTPCache(t *testing.T) {
+	now := time.Now()
+	client := &recordingRacing{fakeRacing: fakeRacing{resp: &racing.ListRacesResponse{Races: []*racing.Race{
+		{Id: 1, AdvertisedStartTime: timestamppb.New(now.Add(-time.Minute)), Status: racing.RaceStatus_CLOSED},
+		{Id: 2, AdvertisedStartTime: timestamppb.New(now.Add(12500 * time.Millisecond)), Status: racing.RaceStatus_OPEN},
+	}}}}
+	mux := newCacheTestMux(t, client, now)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races?filter.meeting_ids=5&filter.only_visible=true&sort.field=name", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []int64{5}, client.req.GetFilter().GetMeetingIds())
+	assert.True(t, client.req.GetFilter().GetOnlyVisible())
+	assert.Equal(t, "name", client.req.GetSort().GetField())
+
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "public, max-age=12", rec.Header().Get("Cache-Control"), "fresh until race 2 jumps")
+	assert.NotEmpty(t, rec.Body.String())
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{name: "current etag", ifNoneMatch: etag, want: http.StatusNotModified},
+		{name: "weak form among others", ifNoneMatch: `"stale", W/` + etag, want: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", want: http.StatusNotModified},
+		{name: "stale etag", ifNoneMatch: `"stale"`, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/races?filter.meeting_ids=5&filter.only_visible=true&sort.field=name", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+			if tt.want == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPCache_MaxAge(t *testing.T) {
+	now := time.Now()
+	client := &fakeRacing{resp: &racing.ListRacesResponse{Races: []*racing.Race{
+		{Id: 1, AdvertisedStartTime: timestamppb.New(now.Add(time.Hour))},
+	}}}
+	mux := newCacheTestMux(t, client, now)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/races", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, "private, max-age=30", rec.Header().Get("Cache-Control"), "capped, and private to the caller")
+}
+
+func TestHTTPCache_Uncached(t *testing.T) {
+	client := &fakeRacing{resp: &racing.ListRacesResponse{}}
+	mux := newCacheTestMux(t, client, time.Now())
+
+	// The POST form of the list is not cacheable.
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/list-races", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+
+	// Nor are errors.
+	client.err = status.Error(codes.Unavailable, "down")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	assert.NotEmpty(t, rec.Body.String())
+}
+
+// batchRacing is a fakeRacing that answers BatchGetRaces with the ids asked
+// for, all found.
+type batchRacing struct {
+	fakeRacing
+	start time.Time
+}
+
+func (f *batchRacing) BatchGetRaces(_ context.Context, req *racing.BatchGetRacesRequest, _ ...grpc.CallOption) (*racing.BatchGetRacesResponse, error) {
+	resp := &racing.BatchGetRacesResponse{}
+	for _, id := range req.Ids {
+		resp.Races = append(resp.Races, &racing.Race{Id: id, AdvertisedStartTime: timestamppb.New(f.start)})
+	}
+	return resp, nil
+}
+
+func TestHTTPCache_BatchGet(t *testing.T) {
+	now := time.Now()
+	client := &batchRacing{start: now.Add(5 * time.Second)}
+	mux := newCacheTestMux(t, client, now)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/races:batchGet?ids=2&ids=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"races":[{"id":"2"`)
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, "public, max-age=5", rec.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/logging"
+)
+
+// requestLog gives each request an id, taken from a valid X-Request-Id header
+// or generated, and returns it in X-Request-Id. The id is put back on the
+// request so forwardedHeaders passes it to the backends, and into the context
+// so every line logged for the request carries it.
+//
+// When the request completes, requestLog writes an access log line to logger
+// with the method, path, query string, status code and duration. Probes and
+// scrapes, which are not traced either, are logged at debug level only.
+func requestLog(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+			r.Header.Set(logging.RequestIDHeader, id)
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		ctx := logging.NewContext(r.Context(), id)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case !traced(r):
+			level = slog.LevelDebug
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, slog.String("query", r.URL.RawQuery))
+		}
+		logger.LogAttrs(ctx, level, "http request", attrs...)
+	})
+}
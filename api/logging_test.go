@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestLog(t *testing.T) {
+	var out bytes.Buffer
+	var forwarded []string
+	handler := requestLog(logging.New(&out, slog.LevelInfo), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		md, _ := metadata.FromOutgoingContext(forwardMetadata(r))
+		forwarded = append(forwarded, md.Get("x-request-id")...)
+		if r.URL.Path == "/v1/nowhere" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	do := func(path, id string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if id != "" {
+			r.Header.Set("X-Request-Id", id)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	// A caller's id is kept, echoed and forwarded.
+	rec := do("/v1/next-to-go?limit=5", "abc-123")
+	assert.Equal(t, "abc-123", rec.Header().Get("X-Request-Id"))
+
+	// Otherwise one is generated, including in place of an unsafe one.
+	generated := do("/v1/nowhere", "").Header().Get("X-Request-Id")
+	assert.Len(t, generated, 32)
+	assert.NotEqual(t, "bad id", do("/readyz", "bad id").Header().Get("X-Request-Id"))
+
+	assert.Equal(t, "abc-123", forwarded[0])
+	assert.Equal(t, generated, forwarded[1])
+
+	// Probes are logged at debug level, so only two lines are written.
+	var lines []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var line map[string]any
+		require.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "http request", lines[0]["msg"])
+	assert.Equal(t, "abc-123", lines[0]["request_id"])
+	assert.Equal(t, "GET", lines[0]["method"])
+	assert.Equal(t, "/v1/next-to-go", lines[0]["path"])
+	assert.Equal(t, "limit=5", lines[0]["query"])
+	assert.Equal(t, 200.0, lines[0]["status"])
+	assert.Contains(t, lines[0], "duration_ms")
+	assert.Equal(t, 404.0, lines[1]["status"])
+}
+
+func TestHeaderMatchers(t *testing.T) {
+	key, ok := headerMatcher("X-Request-Id")
+	assert.True(t, ok)
+	assert.Equal(t, "x-request-id", key)
+
+	key, ok = headerMatcher("X-Api-Key")
+	assert.True(t, ok)
+	assert.Equal(t, "x-api-key", key)
+
+	_, ok = outgoingHeaderMatcher("x-request-id")
+	assert.False(t, ok, "the request id is returned as X-Request-Id already")
+	key, ok = outgoingHeaderMatcher("x-custom")
+	assert.True(t, ok)
+	assert.Equal(t, "Grpc-Metadata-x-custom", key)
+}
@@ -2,44 +2,200 @@ package main
 
 import (
 	"context"
-	"flag"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
-	"git.neds.sh/matty/entain/api/proto/racing"
+	racinggw "git.neds.sh/matty/entain/api/proto/racing"
+	sportsgw "git.neds.sh/matty/entain/api/proto/sports"
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/logging"
+	"github.com/SylvanSol/Entain_Test/common/metrics"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/SylvanSol/Entain_Test/common/shutdown"
+	"github.com/SylvanSol/Entain_Test/common/tlsconfig"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
-)
-
-var (
-	apiEndpoint  = flag.String("api-endpoint", "localhost:8000", "API endpoint")
-	grpcEndpoint = flag.String("grpc-endpoint", "localhost:9000", "gRPC server endpoint")
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
-	flag.Parse()
+	cfg := defaultConfig()
+	printConfig, err := config.Load("API", &cfg, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %s\n", err)
+	}
+	if printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatalf("failed printing configuration: %s\n", err)
+		}
+		return
+	}
 
-	if err := run(); err != nil {
-		log.Printf("failed running api server: %s\n", err)
+	slog.SetDefault(logging.New(os.Stderr, cfg.Logging.SlogLevel()))
+
+	if err := run(cfg); err != nil {
+		slog.Error("failed running api server", "error", err)
+		os.Exit(1)
 	}
 }
 
-func run() error {
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	mux := runtime.NewServeMux()
-	if err := racing.RegisterRacingHandlerFromEndpoint(
-		ctx,
-		mux,
-		*grpcEndpoint,
-		[]grpc.DialOption{grpc.WithInsecure()},
+func run(cfg Config) error {
+	ctx, stop := shutdown.OnSignal()
+	defer stop()
+
+	flushTraces, err := tracing.Setup(ctx, "api", cfg.Tracing.Exporter, cfg.Tracing.Endpoint)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// ctx is done by the time we get here, so flush on a fresh deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := flushTraces(ctx); err != nil {
+			slog.Error("failed flushing trace spans", "error", err)
+		}
+	}()
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS.Enabled {
+		certs, err := tlsconfig.NewReloader(tlsconfig.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+		if err != nil {
+			return err
+		}
+		go certs.Run(ctx, tlsconfig.DefaultReloadInterval)
+		creds = certs.ClientCredentials(cfg.TLS.ServerName)
+	}
+
+	racingConn, err := grpc.NewClient(cfg.Backends.Racing, grpc.WithTransportCredentials(creds), tracing.ClientHandler())
+	if err != nil {
+		return err
+	}
+	defer racingConn.Close()
+
+	sportsConn, err := grpc.NewClient(cfg.Backends.Sports, grpc.WithTransportCredentials(creds), tracing.ClientHandler())
+	if err != nil {
+		return err
+	}
+	defer sportsConn.Close()
+
+	limiter := ratelimit.New(cfg.RateLimit.Default(), cfg.RateLimit.Routes)
+	go limiter.Run(ctx, time.Minute)
+
+	reg := metrics.NewRegistry()
+	cache := newHTTPCache(cfg.HTTP.CacheMaxAge)
+
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithForwardResponseOption(cache.forwardResponse),
+		runtime.WithMarshalerOption(maskedMIME, maskedMarshaler),
+		// Tracing and metrics wrap rate limiting so that 429s are recorded, and
+		// see the 304s the cache answers with.
+		runtime.WithMiddlewares(traceRoute, newHTTPMetrics(reg).middleware, rateLimit(limiter, cfg.RateLimit.Keys()), readMaskFields, cache.middleware),
+	)
+	if err := racinggw.RegisterRacingHandler(ctx, mux, racingConn); err != nil {
+		return err
+	}
+	if err := sportsgw.RegisterSportsHandler(ctx, mux, sportsConn); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath(
+		http.MethodGet,
+		"/v1/search",
+		searchHandler(mux, racing.NewRacingClient(racingConn), sports.NewSportsClient(sportsConn)),
+	); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath(
+		http.MethodGet,
+		"/v1/next-to-go",
+		nextToGoHandler(mux, racing.NewRacingClient(racingConn), sports.NewSportsClient(sportsConn)),
 	); err != nil {
 		return err
 	}
 
-	log.Printf("API server listening on: %s\n", *apiEndpoint)
+	if err := mux.HandlePath(
+		http.MethodGet,
+		"/v1/races/export",
+		exportHandler(mux, racing.NewRacingClient(racingConn), cfg.HTTP.WriteTimeout),
+	); err != nil {
+		return err
+	}
+
+	openAPI, err := openAPIDocument()
+	if err != nil {
+		return err
+	}
+	if err := mux.HandlePath(http.MethodGet, "/openapi.json", openAPIHandler(openAPI)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath(http.MethodGet, "/docs", docsHandler()); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath(http.MethodGet, "/healthz", healthzHandler()); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath(http.MethodGet, "/readyz", readyzHandler(ctx, map[string]healthpb.HealthClient{
+		"racing": healthpb.NewHealthClient(racingConn),
+		"sports": healthpb.NewHealthClient(sportsConn),
+	})); err != nil {
+		return err
+	}
+
+	metricsHandler := metrics.Handler(reg)
+	if err := mux.HandlePath(http.MethodGet, "/metrics", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		metricsHandler.ServeHTTP(w, r)
+	}); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr: cfg.Listen,
+		Handler: requestLog(slog.Default(), otelhttp.NewHandler(mux, "api",
+			otelhttp.WithFilter(traced),
+			otelhttp.WithSpanNameFormatter(traceSpanName),
+		)),
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	slog.Info("API server listening", "address", cfg.Listen)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	// /readyz fails from here on. Keep serving for the drain delay so probes
+	// see it and stop routing here; a second signal exits at once.
+	stop()
+	slog.Info("shutting down, failing readiness before draining", "delay", cfg.HTTP.DrainDelay.String())
+	time.Sleep(cfg.HTTP.DrainDelay)
+
+	slog.Info("shutting down, waiting for in-flight requests", "timeout", cfg.Timeouts.Shutdown.String())
 
-	return http.ListenAndServe(*apiEndpoint, mux)
+	return shutdown.HTTP(srv, cfg.Timeouts.Shutdown)
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	m := newHTTPMetrics(prometheus.NewRegistry())
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(m.middleware))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/races/{id}", func(w http.ResponseWriter, _ *http.Request, params map[string]string) {
+		if params["id"] == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+
+	for _, path := range []string{"/v1/races/1", "/v1/races/2", "/v1/races/404", "/v1/nowhere"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Both ids share the route's series; the unrouted request is not recorded.
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/v1/races/{id=*}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/v1/races/{id=*}", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.requests))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	feedTypeRace  = "race"
+	feedTypeEvent = "event"
+
+	defaultNextToGoLimit = 10
+	maxNextToGoLimit     = 100
+
+	// nextToGoBackendTimeout bounds each backend call, so one slow backend
+	// cannot hold up the whole feed.
+	nextToGoBackendTimeout = 2 * time.Second
+)
+
+// nextToGoResponse is the body of GET /v1/next-to-go.
+type nextToGoResponse struct {
+	Items []feedItem `json:"items"`
+	// NextPageToken fetches the following page when passed as page_token. It is
+	// empty on the last page.
+	NextPageToken string `json:"next_page_token,omitempty"`
+	// Warnings name each backend that could not be reached. When present the
+	// feed is partial.
+	Warnings []feedWarning `json:"warnings,omitempty"`
+}
+
+// feedItem is one upcoming race or sports event. Exactly one of Race and Event
+// is set, according to Type.
+type feedItem struct {
+	// Type is "race" or "event".
+	Type  string          `json:"type"`
+	Race  json.RawMessage `json:"race,omitempty"`
+	Event json.RawMessage `json:"event,omitempty"`
+
+	start time.Time
+	id    int64
+}
+
+type feedWarning struct {
+	// Source is the backend that failed: "racing" or "sports".
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// feedCursor marks the last item of a page. Items are ordered by start time,
+// then type, then id, so the next page starts strictly after the cursor.
+type feedCursor struct {
+	Start int64  `json:"s"`
+	Type  string `json:"t"`
+	ID    int64  `json:"i"`
+}
+
+func cursorOf(item feedItem) feedCursor {
+	return feedCursor{Start: item.start.UnixNano(), Type: item.Type, ID: item.id}
+}
+
+// less reports whether c comes before other in the feed.
+func (c feedCursor) less(other feedCursor) bool {
+	if c.Start != other.Start {
+		return c.Start < other.Start
+	}
+	if c.Type != other.Type {
+		return c.Type < other.Type
+	}
+	return c.ID < other.ID
+}
+
+func encodeCursor(item feedItem) string {
+	b, _ := json.Marshal(cursorOf(item))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(token string) (feedCursor, error) {
+	var c feedCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	return c, err
+}
+
+// nextToGoHandler serves GET /v1/next-to-go?limit=&page_token=, the visible
+// races and sports events that have yet to start, soonest first.
+//
+// Racing and sports are called concurrently, each with its own deadline. If one
+// of them fails the feed is built from the other and a warning names the
+// missing source; only when both fail is the request an error.
+func nextToGoHandler(mux *runtime.ServeMux, racingClient racing.RacingClient, sportsClient sports.SportsClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := forwardMetadata(r)
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+		query := r.URL.Query()
+
+		limit := defaultNextToGoLimit
+		if raw := query.Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxNextToGoLimit {
+				runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+					Field:       "limit",
+					Description: "must be a number from 1 to " + strconv.Itoa(maxNextToGoLimit),
+				}))
+				return
+			}
+			limit = n
+		}
+
+		var cursor *feedCursor
+		if token := query.Get("page_token"); token != "" {
+			c, err := decodeCursor(token)
+			if err != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+					Field:       "page_token",
+					Description: "is not a token returned by this endpoint",
+				}))
+				return
+			}
+			cursor = &c
+		}
+
+		resp, err := nextToGo(ctx, outbound, racingClient, sportsClient, time.Now(), limit, cursor)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// nextToGo fetches both backends and builds one page of the feed.
+func nextToGo(
+	ctx context.Context,
+	m runtime.Marshaler,
+	racingClient racing.RacingClient,
+	sportsClient sports.SportsClient,
+	now time.Time,
+	limit int,
+	cursor *feedCursor,
+) (*nextToGoResponse, error) {
+	var (
+		wg                  sync.WaitGroup
+		races               *racing.ListRacesResponse
+		events              *sports.ListEventsResponse
+		racesErr, eventsErr error
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(ctx, nextToGoBackendTimeout)
+		defer cancel()
+		races, racesErr = racingClient.ListRaces(ctx, &racing.ListRacesRequest{
+			Filter: &racing.ListRacesRequestFilter{OnlyVisible: true},
+			Sorts:  []*racing.Sort{{Field: "advertised_start_time"}},
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(ctx, nextToGoBackendTimeout)
+		defer cancel()
+		events, eventsErr = sportsClient.ListEvents(ctx, &sports.ListEventsRequest{})
+	}()
+	wg.Wait()
+
+	if racesErr != nil && eventsErr != nil {
+		return nil, status.Errorf(codes.Unavailable, "racing and sports are both unavailable")
+	}
+
+	resp := &nextToGoResponse{Items: []feedItem{}}
+	if racesErr != nil {
+		resp.Warnings = append(resp.Warnings, backendWarning("racing", racesErr))
+	}
+	if eventsErr != nil {
+		resp.Warnings = append(resp.Warnings, backendWarning("sports", eventsErr))
+	}
+
+	// Races and events are both judged upcoming against the gateway's clock, so
+	// the feed never mixes two ideas of "now". A cached race list may still say
+	// OPEN for a race that has jumped.
+	upcoming := func(start time.Time) bool { return start.After(now) }
+
+	var items []feedItem
+	for _, race := range races.GetRaces() {
+		// Abandoned races never jump.
+		if race.Status == racing.RaceStatus_ABANDONED || !upcoming(race.AdvertisedStartTime.AsTime()) {
+			continue
+		}
+		items = append(items, feedItem{
+			Type:  feedTypeRace,
+			Race:  marshalRaw(m, race),
+			start: race.AdvertisedStartTime.AsTime(),
+			id:    race.Id,
+		})
+	}
+	for _, event := range events.GetEvents() {
+		if !upcoming(event.AdvertisedStartTime.AsTime()) {
+			continue
+		}
+		items = append(items, feedItem{
+			Type:  feedTypeEvent,
+			Event: marshalRaw(m, event),
+			start: event.AdvertisedStartTime.AsTime(),
+			id:    event.Id,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return cursorOf(items[i]).less(cursorOf(items[j]))
+	})
+
+	for _, item := range items {
+		if cursor != nil && !cursor.less(cursorOf(item)) {
+			continue
+		}
+		if len(resp.Items) == limit {
+			resp.NextPageToken = encodeCursor(resp.Items[len(resp.Items)-1])
+			break
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp, nil
+}
+
+func backendWarning(source string, err error) feedWarning {
+	st := status.Convert(err)
+	return feedWarning{
+		Source:  source,
+		Message: fmt.Sprintf("%s unavailable (%s); results are partial", source, code.Code(st.Code())),
+	}
+}
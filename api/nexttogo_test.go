@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeRacing answers ListRaces with a fixed response or error.
+type fakeRacing struct {
+	racing.RacingClient
+	resp *racing.ListRacesResponse
+	err  error
+}
+
+func (f *fakeRacing) ListRaces(context.Context, *racing.ListRacesRequest, ...grpc.CallOption) (*racing.ListRacesResponse, error) {
+	return f.resp, f.err
+}
+
+// fakeSports answers ListEvents with a fixed response or error.
+type fakeSports struct {
+	sports.SportsClient
+	resp *sports.ListEventsResponse
+	err  error
+}
+
+func (f *fakeSports) ListEvents(context.Context, *sports.ListEventsRequest, ...grpc.CallOption) (*sports.ListEventsResponse, error) {
+	return f.resp, f.err
+}
+
+func TestNextToGo(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *timestamppb.Timestamp {
+		return timestamppb.New(now.Add(time.Duration(minutes) * time.Minute))
+	}
+
+	racingClient := &fakeRacing{resp: &racing.ListRacesResponse{Races: []*racing.Race{
+		{Id: 1, AdvertisedStartTime: at(5), Status: racing.RaceStatus_OPEN},
+		{Id: 2, AdvertisedStartTime: at(-5), Status: racing.RaceStatus_CLOSED},
+		{Id: 3, AdvertisedStartTime: at(20), Status: racing.RaceStatus_DELAYED},
+		{Id: 4, AdvertisedStartTime: at(25), Status: racing.RaceStatus_ABANDONED},
+		// Jumped, though the backend has not caught up yet.
+		{Id: 5, AdvertisedStartTime: at(-1), Status: racing.RaceStatus_OPEN},
+	}}}
+	sportsClient := &fakeSports{resp: &sports.ListEventsResponse{Events: []*sports.Event{
+		{Id: 1, AdvertisedStartTime: at(10)},
+		{Id: 2, AdvertisedStartTime: at(-10)},
+		{Id: 3, AdvertisedStartTime: at(20)},
+	}}}
+	m := &runtime.JSONPb{}
+
+	type entry struct {
+		Type string
+		ID   int64
+	}
+	entries := func(resp *nextToGoResponse) []entry {
+		var out []entry
+		for _, item := range resp.Items {
+			out = append(out, entry{item.Type, item.id})
+		}
+		return out
+	}
+
+	// The first page merges both backends by start time, breaking the tie at
+	// +20m on type.
+	page, err := nextToGo(context.Background(), m, racingClient, sportsClient, now, 3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []entry{{"race", 1}, {"event", 1}, {"event", 3}}, entries(page))
+	assert.Empty(t, page.Warnings)
+	assert.NotEmpty(t, page.NextPageToken)
+
+	cursor, err := decodeCursor(page.NextPageToken)
+	assert.NoError(t, err)
+	page, err = nextToGo(context.Background(), m, racingClient, sportsClient, now, 3, &cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []entry{{"race", 3}}, entries(page))
+	assert.Empty(t, page.NextPageToken, "last page should have no token")
+
+	// One backend down gives a partial feed with a warning.
+	down := &fakeSports{err: status.Error(codes.Unavailable, "connection refused")}
+	page, err = nextToGo(context.Background(), m, racingClient, down, now, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []entry{{"race", 1}, {"race", 3}}, entries(page))
+	if assert.Len(t, page.Warnings, 1) {
+		assert.Equal(t, "sports", page.Warnings[0].Source)
+	}
+
+	// Both down is an error.
+	_, err = nextToGo(context.Background(), m, &fakeRacing{err: status.Error(codes.Unavailable, "")}, down, now, 10, nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
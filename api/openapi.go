@@ -0,0 +1,75 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiproto "git.neds.sh/matty/entain/api/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// gatewayOpenAPI documents the routes the gateway serves itself rather than
+// through a proto binding, such as /v1/search. Its definitions may refer to
+// the generated ones.
+//
+//go:embed openapi_gateway.json
+var gatewayOpenAPI []byte
+
+// docsPage renders /openapi.json in the browser without fetching anything else.
+//
+//go:embed docs.html
+var docsPage []byte
+
+// openAPIDocument returns the OpenAPI v2 document for the whole gateway: the
+// one generated from the protos with gatewayOpenAPI merged in.
+func openAPIDocument() ([]byte, error) {
+	var doc, gateway map[string]any
+	if err := json.Unmarshal(apiproto.OpenAPI, &doc); err != nil {
+		return nil, fmt.Errorf("generated OpenAPI document: %w", err)
+	}
+	if err := json.Unmarshal(gatewayOpenAPI, &gateway); err != nil {
+		return nil, fmt.Errorf("gateway OpenAPI document: %w", err)
+	}
+
+	for _, section := range []string{"paths", "definitions"} {
+		merged, _ := doc[section].(map[string]any)
+		if merged == nil {
+			merged = map[string]any{}
+			doc[section] = merged
+		}
+		extra, _ := gateway[section].(map[string]any)
+		for name, value := range extra {
+			if _, ok := merged[name]; ok {
+				return nil, fmt.Errorf("gateway OpenAPI document redefines %s %s", section, name)
+			}
+			merged[name] = value
+		}
+	}
+
+	tags, _ := doc["tags"].([]any)
+	doc["tags"] = append(tags, map[string]any{
+		"name":        "Gateway",
+		"description": "Routes served by the gateway itself, combining or checking the backends.",
+	})
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// openAPIHandler serves GET /openapi.json.
+func openAPIHandler(doc []byte) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(doc)
+	}
+}
+
+// docsHandler serves GET /docs, a page describing every route in
+// /openapi.json.
+func docsHandler() runtime.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(docsPage)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	apiproto "git.neds.sh/matty/entain/api/proto"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the part of an OpenAPI v2 document the drift tests compare.
+type openAPISpec struct {
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Enum       []string                   `json:"enum"`
+	} `json:"definitions"`
+}
+
+// httpBinding is one HTTP route bound to an RPC.
+type httpBinding struct {
+	method, path string
+	rpc          protoreflect.MethodDescriptor
+}
+
+// gatewayRule is an HTTP rule in gateway.yaml.
+type gatewayRule struct {
+	Selector           string        `yaml:"selector"`
+	Get                string        `yaml:"get"`
+	Post               string        `yaml:"post"`
+	Put                string        `yaml:"put"`
+	Patch              string        `yaml:"patch"`
+	Delete             string        `yaml:"delete"`
+	AdditionalBindings []gatewayRule `yaml:"additional_bindings"`
+}
+
+// route returns the rule's method and path.
+func (r gatewayRule) route() (string, string) {
+	for _, route := range []struct{ method, path string }{
+		{"get", r.Get}, {"post", r.Post}, {"put", r.Put}, {"patch", r.Patch}, {"delete", r.Delete},
+	} {
+		if route.path != "" {
+			return route.method, route.path
+		}
+	}
+	return "", ""
+}
+
+// gatewayBindings reads the HTTP rules in gateway.yaml, checking that each
+// names a real RPC.
+func gatewayBindings(t *testing.T) []httpBinding {
+	raw, err := os.ReadFile("proto/gateway.yaml")
+	require.NoError(t, err)
+
+	var config struct {
+		HTTP struct {
+			Rules []gatewayRule `yaml:"rules"`
+		} `yaml:"http"`
+	}
+	require.NoError(t, yaml.Unmarshal(raw, &config))
+
+	services := map[protoreflect.FullName]protoreflect.ServiceDescriptor{}
+	for _, file := range []protoreflect.FileDescriptor{racing.File_racing_racing_proto, sports.File_sports_sports_proto} {
+		for i := 0; i < file.Services().Len(); i++ {
+			services[file.Services().Get(i).FullName()] = file.Services().Get(i)
+		}
+	}
+
+	var bindings []httpBinding
+	for _, rule := range config.HTTP.Rules {
+		selector := protoreflect.FullName(rule.Selector)
+		service, ok := services[selector.Parent()]
+		require.True(t, ok, "gateway.yaml binds %s of an unknown service", selector)
+		rpc := service.Methods().ByName(selector.Name())
+		require.NotNil(t, rpc, "gateway.yaml binds unknown RPC %s", selector)
+
+		for _, r := range append([]gatewayRule{rule}, rule.AdditionalBindings...) {
+			method, path := r.route()
+			bindings = append(bindings, httpBinding{method, path, rpc})
+		}
+	}
+	require.NotEmpty(t, bindings)
+	return bindings
+}
+
+// definitionName is the name protoc-gen-openapiv2 gives a message or enum:
+// its package followed by its nested names, e.g. racingListRacesRequestFilter.
+func definitionName(d protoreflect.Descriptor) string {
+	pkg := string(d.ParentFile().Package())
+	return pkg + strings.ReplaceAll(strings.TrimPrefix(string(d.FullName()), pkg+"."), ".", "")
+}
+
+// protoTypes indexes every message and enum in files by definition name.
+func protoTypes(files ...protoreflect.FileDescriptor) map[string]protoreflect.Descriptor {
+	types := map[string]protoreflect.Descriptor{}
+
+	var addMessages func(protoreflect.MessageDescriptors)
+	addEnums := func(enums protoreflect.EnumDescriptors) {
+		for i := 0; i < enums.Len(); i++ {
+			types[definitionName(enums.Get(i))] = enums.Get(i)
+		}
+	}
+	addMessages = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			types[definitionName(messages.Get(i))] = messages.Get(i)
+			addMessages(messages.Get(i).Messages())
+			addEnums(messages.Get(i).Enums())
+		}
+	}
+	for _, file := range files {
+		addMessages(file.Messages())
+		addEnums(file.Enums())
+	}
+	return types
+}
+
+// TestOpenAPI_MatchesProtos fails when api.swagger.json has not been
+// regenerated after a change to the HTTP bindings or the messages they carry.
+func TestOpenAPI_MatchesProtos(t *testing.T) {
+	var generated openAPISpec
+	require.NoError(t, json.Unmarshal(apiproto.OpenAPI, &generated))
+
+	bindings := gatewayBindings(t)
+	bound := map[string]bool{}
+	for _, b := range bindings {
+		bound[b.method+" "+b.path] = true
+		assert.Contains(t, generated.Paths[b.path], b.method, "%s is bound to %s %s but not documented", b.rpc.FullName(), b.method, b.path)
+
+		output := definitionName(b.rpc.Output())
+		assert.Contains(t, generated.Definitions, output, "response of %s is not documented", b.rpc.FullName())
+	}
+	for path, methods := range generated.Paths {
+		for method := range methods {
+			assert.True(t, bound[method+" "+path], "%s %s is documented but no longer bound", method, path)
+		}
+	}
+
+	types := protoTypes(racing.File_racing_racing_proto, sports.File_sports_sports_proto)
+	for name, def := range generated.Definitions {
+		if !strings.HasPrefix(name, "racing") && !strings.HasPrefix(name, "sports") {
+			continue
+		}
+		switch d := types[name].(type) {
+		case protoreflect.MessageDescriptor:
+			var want []string
+			for i := 0; i < d.Fields().Len(); i++ {
+				want = append(want, d.Fields().Get(i).JSONName())
+			}
+			var got []string
+			for property := range def.Properties {
+				got = append(got, property)
+			}
+			sort.Strings(want)
+			sort.Strings(got)
+			assert.Equal(t, want, got, "fields of %s", name)
+		case protoreflect.EnumDescriptor:
+			var want []string
+			for i := 0; i < d.Values().Len(); i++ {
+				want = append(want, string(d.Values().Get(i).Name()))
+			}
+			assert.Equal(t, want, def.Enum, "values of %s", name)
+		default:
+			t.Errorf("%s is documented but is no longer a proto message or enum", name)
+		}
+	}
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	doc, err := openAPIDocument()
+	require.NoError(t, err)
+
+	mux := runtime.NewServeMux()
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/openapi.json", openAPIHandler(doc)))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/docs", docsHandler()))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var served openAPISpec
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	for _, path := range []string{"/v1/list-races", "/v1/races", "/v1/races:batchGet", "/v1/races/export", "/v1/events", "/v1/search", "/v1/next-to-go", "/healthz", "/readyz"} {
+		assert.Contains(t, served.Paths, path)
+	}
+
+	// Every reference, generated or hand-written, resolves.
+	for _, ref := range strings.Split(rec.Body.String(), `"$ref": "#/definitions/`)[1:] {
+		name := ref[:strings.IndexByte(ref, '"')]
+		assert.Contains(t, served.Definitions, name, "dangling $ref")
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `fetch("/openapi.json")`)
+}
@@ -1,3 +1,12 @@
 package proto
 
-//go:generate protoc -I . --go_out . --go_opt paths=source_relative --go-grpc_out . --go-grpc_opt paths=source_relative --grpc-gateway_out . --grpc-gateway_opt paths=source_relative racing/racing.proto --experimental_allow_proto3_optional
+import _ "embed"
+
+//go:generate protoc -I ../../common/proto -I ../../sports/proto --grpc-gateway_out . --grpc-gateway_opt paths=source_relative,standalone=true,grpc_api_configuration=gateway.yaml racing/racing.proto sports/sports.proto
+//go:generate protoc -I ../../common/proto -I ../../sports/proto --openapiv2_out . --openapiv2_opt allow_merge=true,merge_file_name=api,grpc_api_configuration=gateway.yaml,openapi_configuration=openapi.yaml racing/racing.proto sports/sports.proto
+
+// OpenAPI is the OpenAPI v2 document generated from the HTTP bindings of the
+// racing and sports protos.
+//
+//go:embed api.swagger.json
+var OpenAPI []byte
@@ -1,12 +1,12 @@
 // Code generated by protoc-gen-grpc-gateway. DO NOT EDIT.
-// source: racing/racing.proto
+// source: sports/sports.proto
 
 /*
-Package racing is a reverse proxy.
+Package sports is a reverse proxy.
 
 It translates gRPC into RESTful JSON APIs.
 */
-package racing
+package sports
 
 import (
 	"context"
@@ -14,6 +14,7 @@ import (
 	"io"
 	"net/http"
 
+	extSports "github.com/SylvanSol/Entain_Test/sports/proto/sports"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
 	"google.golang.org/grpc"
@@ -35,63 +36,58 @@ var (
 	_ = metadata.Join
 )
 
-func request_Racing_ListRaces_0(ctx context.Context, marshaler runtime.Marshaler, client RacingClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+func request_Sports_ListEvents_0(ctx context.Context, marshaler runtime.Marshaler, client extSports.SportsClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
 	var (
-		protoReq ListRacesRequest
+		protoReq extSports.ListEventsRequest
 		metadata runtime.ServerMetadata
 	)
-	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
-		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
-	}
-	msg, err := client.ListRaces(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	io.Copy(io.Discard, req.Body)
+	msg, err := client.ListEvents(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
 	return msg, metadata, err
 }
 
-func local_request_Racing_ListRaces_0(ctx context.Context, marshaler runtime.Marshaler, server RacingServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+func local_request_Sports_ListEvents_0(ctx context.Context, marshaler runtime.Marshaler, server extSports.SportsServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
 	var (
-		protoReq ListRacesRequest
+		protoReq extSports.ListEventsRequest
 		metadata runtime.ServerMetadata
 	)
-	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
-		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
-	}
-	msg, err := server.ListRaces(ctx, &protoReq)
+	msg, err := server.ListEvents(ctx, &protoReq)
 	return msg, metadata, err
 }
 
-// RegisterRacingHandlerServer registers the http handlers for service Racing to "mux".
-// UnaryRPC     :call RacingServer directly.
+// RegisterSportsHandlerServer registers the http handlers for service Sports to "mux".
+// UnaryRPC     :call SportsServer directly.
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
-// Note that using this registration option will cause many gRPC library features to stop working. Consider using RegisterRacingHandlerFromEndpoint instead.
+// Note that using this registration option will cause many gRPC library features to stop working. Consider using RegisterSportsHandlerFromEndpoint instead.
 // GRPC interceptors will not work for this type of registration. To use interceptors, you must use the "runtime.WithMiddlewares" option in the "runtime.NewServeMux" call.
-func RegisterRacingHandlerServer(ctx context.Context, mux *runtime.ServeMux, server RacingServer) error {
-	mux.Handle(http.MethodPost, pattern_Racing_ListRaces_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+func RegisterSportsHandlerServer(ctx context.Context, mux *runtime.ServeMux, server extSports.SportsServer) error {
+	mux.Handle(http.MethodGet, pattern_Sports_ListEvents_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		var stream runtime.ServerTransportStream
 		ctx = grpc.NewContextWithServerTransportStream(ctx, &stream)
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
-		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/racing.Racing/ListRaces", runtime.WithHTTPPathPattern("/v1/list-races"))
+		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/sports.Sports/ListEvents", runtime.WithHTTPPathPattern("/v1/events"))
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := local_request_Racing_ListRaces_0(annotatedContext, inboundMarshaler, server, req, pathParams)
+		resp, md, err := local_request_Sports_ListEvents_0(annotatedContext, inboundMarshaler, server, req, pathParams)
 		md.HeaderMD, md.TrailerMD = metadata.Join(md.HeaderMD, stream.Header()), metadata.Join(md.TrailerMD, stream.Trailer())
 		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
 		if err != nil {
 			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		forward_Racing_ListRaces_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		forward_Sports_ListEvents_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
 
 	return nil
 }
 
-// RegisterRacingHandlerFromEndpoint is same as RegisterRacingHandler but
+// RegisterSportsHandlerFromEndpoint is same as RegisterSportsHandler but
 // automatically dials to "endpoint" and closes the connection when "ctx" gets done.
-func RegisterRacingHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) (err error) {
+func RegisterSportsHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) (err error) {
 	conn, err := grpc.NewClient(endpoint, opts...)
 	if err != nil {
 		return err
@@ -110,45 +106,45 @@ func RegisterRacingHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMu
 			}
 		}()
 	}()
-	return RegisterRacingHandler(ctx, mux, conn)
+	return RegisterSportsHandler(ctx, mux, conn)
 }
 
-// RegisterRacingHandler registers the http handlers for service Racing to "mux".
+// RegisterSportsHandler registers the http handlers for service Sports to "mux".
 // The handlers forward requests to the grpc endpoint over "conn".
-func RegisterRacingHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
-	return RegisterRacingHandlerClient(ctx, mux, NewRacingClient(conn))
+func RegisterSportsHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterSportsHandlerClient(ctx, mux, extSports.NewSportsClient(conn))
 }
 
-// RegisterRacingHandlerClient registers the http handlers for service Racing
-// to "mux". The handlers forward requests to the grpc endpoint over the given implementation of "RacingClient".
-// Note: the gRPC framework executes interceptors within the gRPC handler. If the passed in "RacingClient"
+// RegisterSportsHandlerClient registers the http handlers for service Sports
+// to "mux". The handlers forward requests to the grpc endpoint over the given implementation of "extSports.SportsClient".
+// Note: the gRPC framework executes interceptors within the gRPC handler. If the passed in "extSports.SportsClient"
 // doesn't go through the normal gRPC flow (creating a gRPC client etc.) then it will be up to the passed in
-// "RacingClient" to call the correct interceptors. This client ignores the HTTP middlewares.
-func RegisterRacingHandlerClient(ctx context.Context, mux *runtime.ServeMux, client RacingClient) error {
-	mux.Handle(http.MethodPost, pattern_Racing_ListRaces_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+// "extSports.SportsClient" to call the correct interceptors. This client ignores the HTTP middlewares.
+func RegisterSportsHandlerClient(ctx context.Context, mux *runtime.ServeMux, client extSports.SportsClient) error {
+	mux.Handle(http.MethodGet, pattern_Sports_ListEvents_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
 		ctx, cancel := context.WithCancel(req.Context())
 		defer cancel()
 		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
-		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/racing.Racing/ListRaces", runtime.WithHTTPPathPattern("/v1/list-races"))
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/sports.Sports/ListEvents", runtime.WithHTTPPathPattern("/v1/events"))
 		if err != nil {
 			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		resp, md, err := request_Racing_ListRaces_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		resp, md, err := request_Sports_ListEvents_0(annotatedContext, inboundMarshaler, client, req, pathParams)
 		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
 		if err != nil {
 			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
 			return
 		}
-		forward_Racing_ListRaces_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+		forward_Sports_ListEvents_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
 	return nil
 }
 
 var (
-	pattern_Racing_ListRaces_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "list-races"}, ""))
+	pattern_Sports_ListEvents_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "events"}, ""))
 )
 
 var (
-	forward_Racing_ListRaces_0 = runtime.ForwardResponseMessage
+	forward_Sports_ListEvents_0 = runtime.ForwardResponseMessage
 )
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// unlimitedPaths are never rate limited, so probes and scrapes keep working
+// under load.
+var unlimitedPaths = []string{"/healthz", "/readyz", "/metrics"}
+
+// rateLimit is mux middleware that refuses requests over the client's limit for
+// their path with a 429, and reports the client's remaining quota on every
+// limited response in the RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers.
+//
+// Clients are named by their X-API-Key header when it is one of keys, or
+// failing that their address.
+func rateLimit(limiter *ratelimit.Limiter, keys ratelimit.KeySet) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if slices.Contains(unlimitedPaths, r.URL.Path) {
+				next(w, r, pathParams)
+				return
+			}
+
+			d := limiter.Allow(r.URL.Path, ratelimit.ClientOf(keys, r.Header.Get(ratelimit.APIKeyHeader), r.RemoteAddr))
+			if d.Limit > 0 {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+				w.Header().Set("RateLimit-Reset", ceilSeconds(d.Reset))
+			}
+			if !d.Allowed {
+				errorHandler(r.Context(), nil, nil, w, r, apierr.ResourceExhausted(r.URL.Path, "rate limit exceeded", d.RetryAfter))
+				return
+			}
+
+			next(w, r, pathParams)
+		}
+	}
+}
+
+// ceilSeconds renders d in whole seconds, rounding up so a client waiting that
+// long is never early.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Limit{Rate: 1, Burst: 2}, map[string]ratelimit.Limit{
+		"/v1/search": {Rate: 1, Burst: 1},
+	})
+	handler := rateLimit(limiter, ratelimit.ParseKeys("k1, k2"))(func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	do := func(path, apiKey string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "203.0.113.9:41000"
+		if apiKey != "" {
+			r.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, r, nil)
+		return rec
+	}
+
+	rec := do("/v1/next-to-go", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, do("/v1/next-to-go", "").Code)
+	rec = do("/v1/next-to-go", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	var body errorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "RESOURCE_EXHAUSTED", body.Error.Status)
+
+	// The same address with an API key is a different client, and each path
+	// has its own limit.
+	assert.Equal(t, http.StatusOK, do("/v1/next-to-go", "k1").Code)
+
+	// An unknown key is ignored, so a fresh one on each request does not get
+	// a fresh bucket.
+	assert.Equal(t, http.StatusTooManyRequests, do("/v1/next-to-go", "random-1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("/v1/next-to-go", "random-2").Code)
+	assert.Equal(t, http.StatusOK, do("/v1/search", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("/v1/search", "").Code)
+
+	// Probes are never limited.
+	for i := 0; i < 5; i++ {
+		rec = do("/readyz", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	}
+}
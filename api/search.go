@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	searchTypeRace  = "race"
+	searchTypeEvent = "event"
+
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// searchResponse is the body of GET /v1/search.
+type searchResponse struct {
+	Results []searchResult `json:"results"`
+}
+
+// searchResult is one typed match. Exactly one of Race and Event is set,
+// according to Type.
+type searchResult struct {
+	// Type is "race" or "event".
+	Type string `json:"type"`
+	// Snippet is the matching text with each hit wrapped in <b></b>.
+	Snippet string `json:"snippet"`
+	// Score is the backend's BM25 relevance; higher is a better match. Races
+	// and events are scored against different indexes, so scores compare only
+	// between results of the same type.
+	Score float64         `json:"score"`
+	Race  json.RawMessage `json:"race,omitempty"`
+	Event json.RawMessage `json:"event,omitempty"`
+
+	// rank is the result's position in its own backend's results.
+	rank  int
+	start *timestamppb.Timestamp
+}
+
+// searchHandler serves GET /v1/search?q=&limit=&types=, searching races and
+// sports events concurrently and interleaving the results by rank.
+//
+// q is free text; the last word is matched as a prefix for type-ahead. limit
+// defaults to 10 and is capped at 50. types is a comma-separated subset of
+// "race,event" and defaults to both.
+func searchHandler(mux *runtime.ServeMux, racingClient racing.RacingClient, sportsClient sports.SportsClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := forwardMetadata(r)
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+		query := r.URL.Query()
+
+		limit := defaultSearchLimit
+		if raw := query.Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxSearchLimit {
+				runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+					Field:       "limit",
+					Description: "must be a number from 1 to " + strconv.Itoa(maxSearchLimit),
+				}))
+				return
+			}
+			limit = n
+		}
+
+		types := map[string]bool{searchTypeRace: true, searchTypeEvent: true}
+		if raw := query.Get("types"); raw != "" {
+			types = map[string]bool{}
+			for _, t := range strings.Split(raw, ",") {
+				t = strings.TrimSpace(t)
+				if t != searchTypeRace && t != searchTypeEvent {
+					runtime.HTTPError(ctx, mux, outbound, w, r, apierr.InvalidArgument(apierr.FieldViolation{
+						Field:       "types",
+						Description: "must list only race and event",
+					}))
+					return
+				}
+				types[t] = true
+			}
+		}
+
+		var (
+			wg                  sync.WaitGroup
+			races               *racing.SearchResponse
+			events              *sports.SearchResponse
+			racesErr, eventsErr error
+		)
+		if types[searchTypeRace] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				races, racesErr = racingClient.Search(ctx, &racing.SearchRequest{Query: query.Get("q"), Limit: int32(limit)})
+			}()
+		}
+		if types[searchTypeEvent] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				events, eventsErr = sportsClient.Search(ctx, &sports.SearchRequest{Query: query.Get("q"), Limit: int32(limit)})
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range []error{racesErr, eventsErr} {
+			if err != nil {
+				runtime.HTTPError(ctx, mux, outbound, w, r, err)
+				return
+			}
+		}
+
+		results := mergeSearch(outbound, races.GetResults(), events.GetResults(), limit)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(searchResponse{Results: results})
+	}
+}
+
+// mergeSearch interleaves the racing and sports results by their rank within
+// each backend, so each backend's best match comes first whatever its raw
+// score. Results of equal rank go soonest start first.
+func mergeSearch(m runtime.Marshaler, races []*racing.SearchResult, events []*sports.SearchResult, limit int) []searchResult {
+	results := []searchResult{}
+	for i, res := range races {
+		results = append(results, searchResult{
+			Type:    searchTypeRace,
+			Snippet: res.Snippet,
+			Score:   res.Score,
+			Race:    marshalRaw(m, res.Race),
+			rank:    i,
+			start:   res.Race.GetAdvertisedStartTime(),
+		})
+	}
+	for i, res := range events {
+		results = append(results, searchResult{
+			Type:    searchTypeEvent,
+			Snippet: res.Snippet,
+			Score:   res.Score,
+			Event:   marshalRaw(m, res.Event),
+			rank:    i,
+			start:   res.Event.GetAdvertisedStartTime(),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].rank != results[j].rank {
+			return results[i].rank < results[j].rank
+		}
+		return results[i].start.AsTime().Before(results[j].start.AsTime())
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// marshalRaw renders msg with the gateway's marshaler so embedded races and
+// events look exactly as they do in the other endpoints.
+func marshalRaw(m runtime.Marshaler, msg proto.Message) json.RawMessage {
+	b, err := m.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMergeSearch(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *timestamppb.Timestamp {
+		return timestamppb.New(now.Add(time.Duration(minutes) * time.Minute))
+	}
+
+	// The sports index scores far higher than the racing one, which must not
+	// push every race below every event.
+	races := []*racing.SearchResult{
+		{Race: &racing.Race{Id: 1, AdvertisedStartTime: at(30)}, Score: 0.5},
+		{Race: &racing.Race{Id: 2, AdvertisedStartTime: at(5)}, Score: 0.2},
+		{Race: &racing.Race{Id: 3, AdvertisedStartTime: at(1)}, Score: 0.1},
+	}
+	events := []*sports.SearchResult{
+		{Event: &sports.Event{Id: 1, AdvertisedStartTime: at(10)}, Score: 9},
+		{Event: &sports.Event{Id: 2, AdvertisedStartTime: at(20)}, Score: 8},
+	}
+	m := &runtime.JSONPb{}
+
+	// ids names each result by type and id, e.g. "race:1".
+	ids := func(results []searchResult) []string {
+		var out []string
+		for _, r := range results {
+			var v struct {
+				ID string `json:"id"`
+			}
+			assert.NoError(t, json.Unmarshal(append(r.Race, r.Event...), &v))
+			out = append(out, r.Type+":"+v.ID)
+		}
+		return out
+	}
+
+	// Each backend's best match comes first; equal ranks go soonest first.
+	assert.Equal(t, []string{"event:1", "race:1", "race:2", "event:2", "race:3"}, ids(mergeSearch(m, races, events, 10)))
+
+	results := mergeSearch(m, races, events, 2)
+	assert.Len(t, results, 2, "the limit applies to the merged results")
+	assert.Equal(t, 9.0, results[0].Score, "scores pass through unchanged")
+
+	results = mergeSearch(m, nil, nil, 10)
+	assert.NotNil(t, results, "no results should encode as an empty list")
+	assert.Empty(t, results)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untracedPaths are polled by probes and scrapers, and would drown out the
+// traces of real requests.
+var untracedPaths = []string{"/healthz", "/readyz", "/metrics"}
+
+// traced reports whether r gets a span.
+func traced(r *http.Request) bool {
+	return !slices.Contains(untracedPaths, r.URL.Path)
+}
+
+// traceSpanName names a request's span before it is routed. Until traceRoute
+// renames it, only the method is known.
+func traceSpanName(_ string, r *http.Request) string {
+	return r.Method
+}
+
+// traceRoute is mux middleware that names the request's span after the route
+// it matched, such as "POST /v1/list-races", and records the route on it.
+func traceRoute(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + pattern.String())
+			span.SetAttributes(semconv.HTTPRoute(pattern.String()))
+		}
+		next(w, r, pathParams)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(traceRoute))
+	ok := func(w http.ResponseWriter, _ *http.Request, _ map[string]string) { w.WriteHeader(http.StatusOK) }
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/v1/races/{id}", ok))
+	require.NoError(t, mux.HandlePath(http.MethodGet, "/healthz", ok))
+
+	handler := otelhttp.NewHandler(mux, "api",
+		otelhttp.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		otelhttp.WithPropagators(propagation.TraceContext{}),
+		otelhttp.WithFilter(traced),
+		otelhttp.WithSpanNameFormatter(traceSpanName),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/races/7", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/nowhere", nil))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2, "probes are not traced")
+
+	// The caller's trace is continued, and the span is named after the route.
+	assert.Equal(t, "GET /v1/races/{id=*}", spans[0].Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+
+	assert.Equal(t, "GET", spans[1].Name(), "unrouted requests keep the method alone")
+}
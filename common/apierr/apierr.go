@@ -0,0 +1,172 @@
+// Package apierr builds the gRPC status errors returned by the racing and sports
+// services, so every failure carries the same machine-readable details.
+//
+//   - InvalidArgument carries a google.rpc.BadRequest listing each bad field.
+//   - NotFound carries a google.rpc.ResourceInfo naming the missing resource.
+//   - FailedPrecondition carries a google.rpc.PreconditionFailure.
+//   - Unauthenticated and PermissionDenied carry a google.rpc.ErrorInfo; for
+//     PermissionDenied its metadata lists the roles that would allow the call.
+//   - ResourceExhausted carries a google.rpc.QuotaFailure and a
+//     google.rpc.RetryInfo saying when to try again.
+//   - Internal carries a google.rpc.RequestInfo holding a correlation id. The
+//     underlying error is logged against that id and never sent to the caller.
+package apierr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// FieldViolation describes a single invalid field in a request.
+type FieldViolation struct {
+	// Field is the path to the field, e.g. "sort.field" or "filter.meeting_ids[2]".
+	Field string
+	// Description explains why the value was rejected.
+	Description string
+}
+
+// InvalidArgument returns an InvalidArgument error listing every violation.
+func InvalidArgument(violations ...FieldViolation) error {
+	details := &errdetails.BadRequest{}
+	for _, v := range violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	msg := "invalid request"
+	if len(violations) == 1 {
+		msg = fmt.Sprintf("invalid %s: %s", violations[0].Field, violations[0].Description)
+	} else if len(violations) > 1 {
+		msg = fmt.Sprintf("invalid request: %d field violations", len(violations))
+	}
+
+	return withDetails(codes.InvalidArgument, msg, details)
+}
+
+// NotFound returns a NotFound error naming the missing resource.
+func NotFound(resourceType string, name string) error {
+	return withDetails(
+		codes.NotFound,
+		fmt.Sprintf("%s %s not found", resourceType, name),
+		&errdetails.ResourceInfo{ResourceType: resourceType, ResourceName: name},
+	)
+}
+
+// FailedPrecondition returns a FailedPrecondition error explaining which
+// condition on subject was not met.
+func FailedPrecondition(conditionType, subject, description string) error {
+	return withDetails(
+		codes.FailedPrecondition,
+		fmt.Sprintf("%s: %s", subject, description),
+		&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: conditionType, Subject: subject, Description: description},
+			},
+		},
+	)
+}
+
+// Domain is the google.rpc.ErrorInfo domain of errors raised by this repository.
+const Domain = "entain"
+
+// Unauthenticated returns an Unauthenticated error for a missing, malformed or
+// expired credential. reason is a short explanation safe to show the caller.
+func Unauthenticated(reason string) error {
+	return withDetails(
+		codes.Unauthenticated,
+		"unauthenticated: "+reason,
+		&errdetails.ErrorInfo{Reason: "UNAUTHENTICATED", Domain: Domain},
+	)
+}
+
+// PermissionDenied returns a PermissionDenied error for a caller that holds
+// none of roles. No roles means method is not open to any caller.
+func PermissionDenied(method string, roles []string) error {
+	msg := fmt.Sprintf("permission denied: %s is not available", method)
+	if len(roles) > 0 {
+		msg = fmt.Sprintf("permission denied: %s needs one of the roles %s", method, strings.Join(roles, ", "))
+	}
+
+	return withDetails(
+		codes.PermissionDenied,
+		msg,
+		&errdetails.ErrorInfo{
+			Reason:   "MISSING_ROLE",
+			Domain:   Domain,
+			Metadata: map[string]string{"method": method, "required_roles": strings.Join(roles, ",")},
+		},
+	)
+}
+
+// ResourceExhausted returns a ResourceExhausted error for a client that has run
+// out of quota, telling it to retry after retryAfter.
+func ResourceExhausted(subject, description string, retryAfter time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, fmt.Sprintf("%s: %s", subject, description)).WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{Subject: subject, Description: description}},
+		},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, description)
+	}
+	return st.Err()
+}
+
+// FromContext returns a Canceled or DeadlineExceeded error naming op when err
+// was caused by a cancelled or expired context, and nil otherwise.
+func FromContext(op string, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "%s: request cancelled", op)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "%s: took too long", op)
+	default:
+		return nil
+	}
+}
+
+// Internal logs err against a fresh correlation id and returns an Internal
+// error that carries only that id, so SQL text and other internals never reach
+// the caller. The log line carries ctx's request id too.
+func Internal(ctx context.Context, op string, err error) error {
+	id := newCorrelationID()
+	slog.ErrorContext(ctx, "internal error", "correlation_id", id, "op", op, "error", err)
+
+	return withDetails(
+		codes.Internal,
+		fmt.Sprintf("internal error (correlation id %s)", id),
+		&errdetails.RequestInfo{RequestId: id},
+	)
+}
+
+func withDetails(code codes.Code, msg string, details protoadapt.MessageV1) error {
+	st, err := status.New(code, msg).WithDetails(details)
+	if err != nil {
+		// Only fails if details cannot be marshalled into an Any.
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package apierr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInvalidArgument_FieldViolations(t *testing.T) {
+	err := InvalidArgument(
+		FieldViolation{Field: "sort.field", Description: "unknown field"},
+		FieldViolation{Field: "filter.meeting_ids[0]", Description: "must be positive"},
+	)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		br, ok := st.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok, "expected BadRequest detail")
+		assert.Len(t, br.FieldViolations, 2)
+		assert.Equal(t, "sort.field", br.FieldViolations[0].Field)
+	}
+}
+
+func TestNotFound_ResourceInfo(t *testing.T) {
+	st := status.Convert(NotFound("race", "42"))
+	assert.Equal(t, codes.NotFound, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		ri, ok := st.Details()[0].(*errdetails.ResourceInfo)
+		assert.True(t, ok, "expected ResourceInfo detail")
+		assert.Equal(t, "race", ri.ResourceType)
+		assert.Equal(t, "42", ri.ResourceName)
+	}
+}
+
+func TestInternal_HidesCause(t *testing.T) {
+	st := status.Convert(Internal(context.Background(), "list races", errors.New("no such column: meedting_id")))
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.NotContains(t, st.Message(), "meedting_id")
+	if assert.Len(t, st.Details(), 1) {
+		ri, ok := st.Details()[0].(*errdetails.RequestInfo)
+		assert.True(t, ok, "expected RequestInfo detail")
+		assert.NotEmpty(t, ri.RequestId)
+		assert.Contains(t, st.Message(), ri.RequestId)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	err := FromContext("list races", fmt.Errorf("%w: interrupted", context.DeadlineExceeded))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, "list races: took too long", status.Convert(err).Message())
+
+	assert.Equal(t, codes.Canceled, status.Code(FromContext("get race", context.Canceled)))
+	assert.Nil(t, FromContext("get race", errors.New("disk I/O error")))
+}
+
+func TestPermissionDenied_ErrorInfo(t *testing.T) {
+	st := status.Convert(PermissionDenied("/racing.Racing/AbandonRace", []string{"trader", "admin"}))
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.True(t, ok, "expected ErrorInfo detail")
+		assert.Equal(t, "MISSING_ROLE", info.Reason)
+		assert.Equal(t, "trader,admin", info.Metadata["required_roles"])
+	}
+}
@@ -0,0 +1,240 @@
+// Package auth authenticates callers of the gRPC services with JWT bearer
+// tokens and authorises each RPC against the caller's roles.
+//
+// Tokens arrive in the "authorization" metadata as "Bearer <jwt>". They are
+// verified against the keys of a local JWKS file, selected by the token's "kid"
+// header, and must carry the configured issuer and audience. The caller's roles
+// are read from the "roles" claim.
+//
+// A request without a token is served as an anonymous caller with no roles, so
+// public RPCs keep working. A token that is present but invalid is always
+// rejected.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// clockSkew is the leeway allowed on a token's time claims.
+const clockSkew = time.Minute
+
+// signatureAlgorithms are the algorithms a token may be signed with.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Caller is the authenticated identity behind a request.
+type Caller struct {
+	// Subject is the token's "sub" claim.
+	Subject string
+	// Roles are the token's "roles" claim.
+	Roles []string
+}
+
+// HasAnyRole reports whether the caller holds at least one of roles. A nil
+// caller holds none.
+func (c *Caller) HasAnyRole(roles ...string) bool {
+	if c == nil {
+		return false
+	}
+	for _, role := range roles {
+		if slices.Contains(c.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type callerKey struct{}
+
+// NewContext returns a copy of ctx carrying caller.
+func NewContext(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// FromContext returns the caller stored in ctx, or nil for an anonymous caller.
+func FromContext(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(callerKey{}).(*Caller)
+	return caller
+}
+
+// Verifier checks bearer tokens against a JWKS.
+type Verifier struct {
+	keys     jose.JSONWebKeySet
+	issuer   string
+	audience string
+}
+
+// NewVerifier loads the JWKS file at path. Tokens must name issuer in "iss" and
+// include audience in "aud"; either check is skipped when empty.
+func NewVerifier(path, issuer, audience string) (*Verifier, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	v := &Verifier{issuer: issuer, audience: audience}
+	if err := json.Unmarshal(b, &v.keys); err != nil {
+		return nil, fmt.Errorf("auth: %s: %w", path, err)
+	}
+	if len(v.keys.Keys) == 0 {
+		return nil, fmt.Errorf("auth: %s holds no keys", path)
+	}
+	return v, nil
+}
+
+// Verify checks token's signature and claims and returns its caller.
+func (v *Verifier) Verify(token string) (*Caller, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		std    jwt.Claims
+		custom struct {
+			Roles []string `json:"roles"`
+		}
+	)
+	if err := parsed.Claims(v.keys, &std, &custom); err != nil {
+		return nil, err
+	}
+
+	expected := jwt.Expected{Issuer: v.issuer, Time: time.Now()}
+	if v.audience != "" {
+		expected.AnyAudience = jwt.Audience{v.audience}
+	}
+	if err := std.ValidateWithLeeway(expected, clockSkew); err != nil {
+		return nil, err
+	}
+	if std.Expiry == nil {
+		return nil, jwt.ErrExpired
+	}
+
+	return &Caller{Subject: std.Subject, Roles: custom.Roles}, nil
+}
+
+// Rule says who may call an RPC.
+type Rule struct {
+	// Roles lists the roles that may call the RPC; the caller needs any one of
+	// them. Empty means any caller, including an anonymous one.
+	Roles []string
+
+	// ForRequest, when set, returns roles needed for this particular request,
+	// on top of Roles, or nil if it needs none. It sees the decoded request.
+	ForRequest func(req any) []string
+}
+
+// Policy maps full RPC method names, e.g. "/racing.Racing/ListRaces", to the
+// rule for that RPC. Methods missing from the policy are denied to everyone.
+type Policy map[string]Rule
+
+// Public is the rule for an RPC anyone may call.
+var Public = Rule{}
+
+// UnaryServerInterceptor authenticates each request with v and authorises it
+// against policy. A nil v accepts no tokens, so only public RPCs can be called.
+// The caller is available to handlers through FromContext.
+func UnaryServerInterceptor(v *Verifier, policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		caller, err := authenticate(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+
+		rule, ok := policy[info.FullMethod]
+		if !ok {
+			return nil, apierr.PermissionDenied(info.FullMethod, nil)
+		}
+		if err := authorise(info.FullMethod, caller, rule.Roles); err != nil {
+			return nil, err
+		}
+		if rule.ForRequest != nil {
+			if err := authorise(info.FullMethod, caller, rule.ForRequest(req)); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(NewContext(ctx, caller), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs. A
+// stream has no single request for ForRequest to look at, so a stream whose
+// rule has one is denied to everyone rather than let through unchecked.
+func StreamServerInterceptor(v *Verifier, policy Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		caller, err := authenticate(ss.Context(), v)
+		if err != nil {
+			return err
+		}
+
+		rule, ok := policy[info.FullMethod]
+		if !ok || rule.ForRequest != nil {
+			return apierr.PermissionDenied(info.FullMethod, nil)
+		}
+		if err := authorise(info.FullMethod, caller, rule.Roles); err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: NewContext(ss.Context(), caller)})
+	}
+}
+
+// serverStream is a grpc.ServerStream with its context replaced.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// authenticate returns the caller named by the request's bearer token, or nil
+// when there is no token.
+func authenticate(ctx context.Context, v *Verifier) (*Caller, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return nil, apierr.Unauthenticated("authorization must be a bearer token")
+	}
+	if v == nil {
+		return nil, apierr.Unauthenticated("this service is not configured to accept tokens")
+	}
+
+	caller, err := v.Verify(token)
+	if err != nil {
+		return nil, apierr.Unauthenticated("invalid bearer token: " + err.Error())
+	}
+	return caller, nil
+}
+
+// authorise checks that caller holds one of roles, if any are required.
+func authorise(method string, caller *Caller, roles []string) error {
+	if len(roles) == 0 || caller.HasAnyRole(roles...) {
+		return nil
+	}
+	if caller == nil {
+		return apierr.Unauthenticated(method + " needs a bearer token")
+	}
+	return apierr.PermissionDenied(method, roles)
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testIssuer signs tokens with a key generated for the test.
+type testIssuer struct {
+	t      *testing.T
+	signer jose.Signer
+	jwks   string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.ES256), Use: "sig"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwks, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testIssuer{t: t, signer: signer, jwks: jwks}
+}
+
+// token signs a token for the test issuer and audience, valid for expiresIn.
+func (i *testIssuer) token(expiresIn time.Duration, roles ...string) string {
+	claims := jwt.Claims{
+		Subject:  "punter",
+		Issuer:   "https://auth.test",
+		Audience: jwt.Audience{"racing"},
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		Expiry:   jwt.NewNumericDate(time.Now().Add(expiresIn)),
+	}
+	token, err := jwt.Signed(i.signer).Claims(claims).Claims(map[string]any{"roles": roles}).Serialize()
+	if err != nil {
+		i.t.Fatal(err)
+	}
+	return token
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier, err := NewVerifier(issuer.jwks, "https://auth.test", "racing")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	policy := Policy{
+		"/test.Test/Public":   Public,
+		"/test.Test/Internal": {Roles: []string{"internal", "admin"}},
+		// A request of true asks for something only internal callers may see.
+		"/test.Test/Filtered": {ForRequest: func(req any) []string {
+			if req.(bool) {
+				return []string{"internal"}
+			}
+			return nil
+		}},
+	}
+	interceptor := UnaryServerInterceptor(verifier, policy)
+
+	tests := []struct {
+		name          string
+		method        string
+		req           any
+		authorization string
+		wantCode      codes.Code
+		wantSubject   string
+	}{
+		{name: "anonymous public", method: "/test.Test/Public"},
+		{name: "authenticated public", method: "/test.Test/Public", authorization: "Bearer " + issuer.token(time.Hour), wantSubject: "punter"},
+		{name: "anonymous internal", method: "/test.Test/Internal", wantCode: codes.Unauthenticated},
+		{name: "missing role", method: "/test.Test/Internal", authorization: "Bearer " + issuer.token(time.Hour, "punter"), wantCode: codes.PermissionDenied},
+		{name: "holds a role", method: "/test.Test/Internal", authorization: "Bearer " + issuer.token(time.Hour, "admin"), wantSubject: "punter"},
+		{name: "expired token", method: "/test.Test/Public", authorization: "Bearer " + issuer.token(-time.Hour), wantCode: codes.Unauthenticated},
+		{name: "tampered token", method: "/test.Test/Public", authorization: "Bearer " + issuer.token(time.Hour) + "x", wantCode: codes.Unauthenticated},
+		{name: "token from another issuer", method: "/test.Test/Public", authorization: "Bearer " + newTestIssuer(t).token(time.Hour), wantCode: codes.Unauthenticated},
+		{name: "not a bearer token", method: "/test.Test/Public", authorization: "Basic dXNlcjpwYXNz", wantCode: codes.Unauthenticated},
+		{name: "unlisted method", method: "/test.Test/Secret", authorization: "Bearer " + issuer.token(time.Hour, "admin"), wantCode: codes.PermissionDenied},
+		{name: "request needs no role", method: "/test.Test/Filtered", req: false},
+		{name: "request needs a role", method: "/test.Test/Filtered", req: true, wantCode: codes.Unauthenticated},
+		{name: "request role held", method: "/test.Test/Filtered", req: true, authorization: "Bearer " + issuer.token(time.Hour, "internal"), wantSubject: "punter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+			req := tt.req
+			if req == nil {
+				req = false
+			}
+
+			var subject string
+			_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ any) (any, error) {
+				if caller := FromContext(ctx); caller != nil {
+					subject = caller.Subject
+				}
+				return nil, nil
+			})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantSubject, subject)
+		})
+	}
+}
+
+// fakeStream is a grpc.ServerStream with only a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier, err := NewVerifier(issuer.jwks, "https://auth.test", "racing")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	policy := Policy{
+		"/test.Test/Public":   Public,
+		"/test.Test/Internal": {Roles: []string{"internal"}},
+		"/test.Test/Filtered": {ForRequest: func(any) []string { return nil }},
+	}
+	interceptor := StreamServerInterceptor(verifier, policy)
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantCode      codes.Code
+		wantSubject   string
+	}{
+		{name: "anonymous public", method: "/test.Test/Public"},
+		{name: "anonymous internal", method: "/test.Test/Internal", wantCode: codes.Unauthenticated},
+		{name: "missing role", method: "/test.Test/Internal", authorization: "Bearer " + issuer.token(time.Hour, "punter"), wantCode: codes.PermissionDenied},
+		{name: "holds a role", method: "/test.Test/Internal", authorization: "Bearer " + issuer.token(time.Hour, "internal"), wantSubject: "punter"},
+		{name: "tampered token", method: "/test.Test/Public", authorization: "Bearer " + issuer.token(time.Hour) + "x", wantCode: codes.Unauthenticated},
+		{name: "unlisted method", method: "/test.Test/Secret", authorization: "Bearer " + issuer.token(time.Hour, "internal"), wantCode: codes.PermissionDenied},
+		{name: "rule needs a request", method: "/test.Test/Filtered", authorization: "Bearer " + issuer.token(time.Hour, "internal"), wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			var subject string
+			err := interceptor(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(_ any, ss grpc.ServerStream) error {
+				if caller := FromContext(ss.Context()); caller != nil {
+					subject = caller.Subject
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantSubject, subject)
+		})
+	}
+}
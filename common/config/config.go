@@ -0,0 +1,282 @@
+// Package config loads a binary's settings from, in increasing order of
+// precedence, its built-in defaults, an optional YAML file, environment
+// variables and command-line flags.
+//
+// Settings are the fields of a struct, tagged with their YAML key, flag name
+// and usage:
+//
+//	type Config struct {
+//		Listen string     `yaml:"listen" flag:"listen" usage:"address to listen on"`
+//		TLS    config.TLS `yaml:"tls"`
+//	}
+//
+// Nested structs are walked, and their fields carry their own flag names. Each
+// flag can also be set through an environment variable named after it: with the
+// prefix "RACING", -listen is RACING_LISTEN and -tls-cert is RACING_TLS_CERT.
+// Supported field types are string, bool, int, float64 and time.Duration.
+// Fields without a flag tag, such as maps, can only be set from the file.
+//
+// Every binary also accepts -config (or PREFIX_CONFIG), the path of the YAML
+// file, and -print-config, which asks the caller to print the effective
+// configuration and exit.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is implemented by configuration structs that check their own
+// settings. Load calls Validate on the configuration and on every nested struct
+// that implements it.
+type Validator interface {
+	Validate() error
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting is one flag-backed field of a configuration struct.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	value reflect.Value
+}
+
+// Load fills cfg, a pointer to a struct already holding the defaults, from the
+// YAML file, environment and args, in that order, and validates the result.
+// prefix names the environment variables, e.g. "RACING".
+//
+// printConfig reports whether -print-config was given; the caller should then
+// print cfg with Print and exit.
+func Load(prefix string, cfg any, args []string) (printConfig bool, err error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return false, fmt.Errorf("config: Load needs a pointer to a struct, got %T", cfg)
+	}
+
+	settings, err := collect(v.Elem(), prefix)
+	if err != nil {
+		return false, err
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configFile := fs.String("config", os.Getenv(prefix+"_CONFIG"), "path to a YAML configuration file (env "+prefix+"_CONFIG)")
+	fs.BoolVar(&printConfig, "print-config", false, "print the effective configuration as YAML and exit")
+
+	// Flags are parsed first to find the YAML file, but applied last so they take
+	// precedence over it and over the environment.
+	for _, s := range settings {
+		fs.Var(&flagValue{def: format(s.value), boolean: s.value.Kind() == reflect.Bool}, s.flag, s.usage+" (env "+s.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, cfg); err != nil {
+			return false, err
+		}
+	}
+
+	for _, s := range settings {
+		raw, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := set(s.value, raw); err != nil {
+			return false, fmt.Errorf("config: %s: %w", s.env, err)
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		fv, ok := f.Value.(*flagValue)
+		if !ok || flagErr != nil {
+			return
+		}
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if err := set(s.value, fv.raw); err != nil {
+					flagErr = fmt.Errorf("config: -%s: %w", f.Name, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return false, flagErr
+	}
+
+	if err := validate(v.Elem()); err != nil {
+		return false, fmt.Errorf("config: %w", err)
+	}
+
+	return printConfig, nil
+}
+
+// Print writes cfg to w as YAML, in the same shape the configuration file takes.
+func Print(w io.Writer, cfg any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func loadFile(path string, cfg any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	// A misspelt key would otherwise be ignored silently.
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+// collect returns every flag-backed field of the struct v, walking nested
+// structs.
+func collect(v reflect.Value, prefix string) ([]setting, error) {
+	var settings []setting
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct {
+			nested, err := collect(fv, prefix)
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, nested...)
+			continue
+		}
+
+		name := field.Tag.Get("flag")
+		if name == "" {
+			continue
+		}
+		if !supported(fv.Type()) {
+			return nil, fmt.Errorf("config: field %s has unsupported type %s", field.Name, fv.Type())
+		}
+
+		settings = append(settings, setting{
+			flag:  name,
+			env:   prefix + "_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")),
+			usage: field.Tag.Get("usage"),
+			value: fv,
+		})
+	}
+
+	return settings, nil
+}
+
+// validate calls Validate on v and on each nested struct, innermost first.
+func validate(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() && v.Field(i).Kind() == reflect.Struct {
+			if err := validate(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if validator, ok := v.Addr().Interface().(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+		return true
+	}
+	return t == durationType
+}
+
+// set parses raw into the field v according to its type.
+func set(v reflect.Value, raw string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// format renders the field v the way set parses it.
+func format(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// flagValue records the raw text of a flag so it can be applied after the file
+// and environment.
+type flagValue struct {
+	def     string
+	raw     string
+	boolean bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.def
+}
+
+func (f *flagValue) Set(raw string) error {
+	f.raw = raw
+	return nil
+}
+
+// IsBoolFlag lets boolean settings be given as a bare -flag.
+func (f *flagValue) IsBoolFlag() bool {
+	return f.boolean
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Listen   string   `yaml:"listen" flag:"listen" usage:"address to listen on"`
+	Debug    bool     `yaml:"debug" flag:"debug"`
+	DB       DB       `yaml:"db"`
+	Timeouts Timeouts `yaml:"timeouts"`
+}
+
+func (c *testConfig) Validate() error {
+	return ValidateAddress("listen", c.Listen)
+}
+
+func defaults() testConfig {
+	return testConfig{
+		Listen:   "localhost:9000",
+		DB:       DB{DSN: ":memory:", Seed: SeedDummy},
+		Timeouts: Timeouts{Shutdown: 10 * time.Second},
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	file := writeFile(t, `
+listen: file:1
+db:
+  dsn: file.db
+timeouts:
+  shutdown: 30s
+`)
+	t.Setenv("TEST_CONFIG", file)
+	t.Setenv("TEST_LISTEN", "env:2")
+	t.Setenv("TEST_DB_SEED", "none")
+
+	cfg := defaults()
+	printConfig, err := Load("TEST", &cfg, []string{"-listen", "flag:3", "-debug"})
+	assert.NoError(t, err)
+	assert.False(t, printConfig)
+
+	assert.Equal(t, "flag:3", cfg.Listen, "flags beat the environment")
+	assert.Equal(t, SeedNone, cfg.DB.Seed, "the environment beats the file")
+	assert.Equal(t, "file.db", cfg.DB.DSN, "the file beats defaults")
+	assert.Equal(t, 30*time.Second, cfg.Timeouts.Shutdown)
+	assert.True(t, cfg.Debug)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{name: "bad address", args: []string{"-listen", "nowhere"}, wantErr: "listen"},
+		{name: "unknown seed mode", env: map[string]string{"TEST_DB_SEED": "lots"}, wantErr: "db.seed"},
+		{name: "unparsable duration", args: []string{"-shutdown-timeout", "soon"}, wantErr: "-shutdown-timeout"},
+		{name: "zero duration", env: map[string]string{"TEST_SHUTDOWN_TIMEOUT": "0s"}, wantErr: "timeouts.shutdown"},
+		{name: "negative query timeout", args: []string{"-db-query-timeout", "-1s"}, wantErr: "db.query_timeout"},
+		{name: "misspelt file key", file: "listn: localhost:1\n", wantErr: "listn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "-config", writeFile(t, tt.file))
+			}
+
+			cfg := defaults()
+			_, err := Load("TEST", &cfg, args)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	cfg := defaults()
+	printConfig, err := Load("TEST", &cfg, []string{"-print-config"})
+	assert.NoError(t, err)
+	assert.True(t, printConfig)
+
+	var out bytes.Buffer
+	assert.NoError(t, Print(&out, cfg))
+	assert.Equal(t, `listen: localhost:9000
+debug: false
+db:
+  dsn: ':memory:'
+  seed: dummy
+  query_timeout: 0s
+timeouts:
+  shutdown: 10s
+`, out.String())
+}
+
+func TestPrint_RedactsAPIKeys(t *testing.T) {
+	type keyedConfig struct {
+		RateLimit RateLimit `yaml:"ratelimit"`
+	}
+	cfg := keyedConfig{RateLimit: RateLimit{Rate: 20, Burst: 40}}
+	t.Setenv("TEST_RATELIMIT_API_KEYS", "partner-a-secret, partner-b-secret")
+	_, err := Load("TEST", &cfg, []string{"-print-config"})
+	assert.NoError(t, err)
+	assert.Len(t, cfg.RateLimit.Keys(), 2, "the keys are still loaded")
+
+	var out bytes.Buffer
+	assert.NoError(t, Print(&out, cfg))
+	assert.NotContains(t, out.String(), "secret")
+	assert.Contains(t, out.String(), "api_keys: <2 keys redacted>")
+}
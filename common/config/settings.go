@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+)
+
+// Seed modes for DB.Seed.
+const (
+	// SeedDummy loads the example data on start-up.
+	SeedDummy = "dummy"
+	// SeedNone creates the schema only.
+	SeedNone = "none"
+)
+
+// DB holds the database settings of a backend service.
+type DB struct {
+	// DSN is the go-sqlite3 data source name, a file path or ":memory:".
+	DSN  string `yaml:"dsn" flag:"db-dsn" usage:"SQLite data source name"`
+	Seed string `yaml:"seed" flag:"db-seed" usage:"dummy to load example data on start-up, none for the schema only"`
+	// QueryTimeout bounds each query; zero leaves only the request deadline.
+	QueryTimeout time.Duration `yaml:"query_timeout" flag:"db-query-timeout" usage:"longest a single database query may run, 0 for no limit"`
+}
+
+func (d *DB) Validate() error {
+	if d.DSN == "" {
+		return errors.New("db.dsn must be set")
+	}
+	if d.Seed != SeedDummy && d.Seed != SeedNone {
+		return fmt.Errorf("db.seed must be %s or %s, got %q", SeedDummy, SeedNone, d.Seed)
+	}
+	if d.QueryTimeout < 0 {
+		return errors.New("db.query_timeout must not be negative")
+	}
+	return nil
+}
+
+// TLS holds a server's certificate. The server speaks TLS when both files are
+// set and plaintext when neither is. Setting ClientCAFile as well requires
+// clients to present a certificate signed by it (mutual TLS).
+type TLS struct {
+	CertFile     string `yaml:"cert_file" flag:"tls-cert" usage:"PEM certificate to serve TLS with"`
+	KeyFile      string `yaml:"key_file" flag:"tls-key" usage:"PEM private key for the TLS certificate"`
+	ClientCAFile string `yaml:"client_ca_file" flag:"tls-client-ca" usage:"PEM CA bundle that client certificates must be signed by, enabling mutual TLS"`
+}
+
+// Enabled reports whether TLS is configured.
+func (t *TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+func (t *TLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("tls.cert_file and tls.key_file must be set together")
+	}
+	if t.ClientCAFile != "" && t.CertFile == "" {
+		return errors.New("tls.client_ca_file needs tls.cert_file and tls.key_file")
+	}
+	return nil
+}
+
+// Auth holds the settings for verifying JWT bearer tokens. Without a JWKS file
+// no token is accepted, so only public RPCs can be called.
+type Auth struct {
+	JWKSFile string `yaml:"jwks_file" flag:"auth-jwks" usage:"JWKS file holding the keys bearer tokens are signed with"`
+	Issuer   string `yaml:"issuer" flag:"auth-issuer" usage:"issuer bearer tokens must name in iss"`
+	Audience string `yaml:"audience" flag:"auth-audience" usage:"audience bearer tokens must include in aud"`
+}
+
+func (a *Auth) Validate() error {
+	if a.JWKSFile == "" && (a.Issuer != "" || a.Audience != "") {
+		return errors.New("auth.issuer and auth.audience need auth.jwks_file")
+	}
+	return nil
+}
+
+// RateLimit configures the per-client token buckets of a binary. A client may
+// make Rate requests a second on average and Burst at once; a Rate of zero
+// disables limiting.
+type RateLimit struct {
+	Rate  float64 `yaml:"rate" flag:"ratelimit-rate" usage:"requests per second each client may make on average, 0 for no limit"`
+	Burst int     `yaml:"burst" flag:"ratelimit-burst" usage:"requests each client may make at once"`
+	// APIKeys lists the API keys given buckets of their own. Requests with any
+	// other key are limited by address.
+	APIKeys string `yaml:"api_keys" flag:"ratelimit-api-keys" usage:"comma-separated API keys limited separately; other keys are limited by address"`
+	// Routes overrides Rate and Burst for particular HTTP paths or gRPC methods.
+	Routes map[string]ratelimit.Limit `yaml:"routes"`
+}
+
+// Default returns the limit applied to routes without an override.
+func (r *RateLimit) Default() ratelimit.Limit {
+	return ratelimit.Limit{Rate: r.Rate, Burst: r.Burst}
+}
+
+// Keys returns the API keys given buckets of their own.
+func (r *RateLimit) Keys() ratelimit.KeySet {
+	return ratelimit.ParseKeys(r.APIKeys)
+}
+
+// MarshalYAML prints how many API keys are configured rather than the keys, so
+// -print-config never writes them to a terminal or deploy log.
+func (r RateLimit) MarshalYAML() (any, error) {
+	type plain RateLimit
+	p := plain(r)
+	if n := len(r.Keys()); n > 0 {
+		p.APIKeys = fmt.Sprintf("<%d keys redacted>", n)
+	}
+	return p, nil
+}
+
+func (r *RateLimit) Validate() error {
+	limits := map[string]ratelimit.Limit{"ratelimit": r.Default()}
+	for route, limit := range r.Routes {
+		limits["ratelimit.routes["+route+"]"] = limit
+	}
+	for name, limit := range limits {
+		if limit.Rate < 0 {
+			return fmt.Errorf("%s.rate must not be negative", name)
+		}
+		if limit.Rate > 0 && limit.Burst < 1 {
+			return fmt.Errorf("%s.burst must be at least 1", name)
+		}
+	}
+	return nil
+}
+
+// Metrics holds the address a backend service serves Prometheus metrics on,
+// at /metrics. An empty Listen turns the endpoint off.
+type Metrics struct {
+	Listen string `yaml:"listen" flag:"metrics-endpoint" usage:"HTTP endpoint serving /metrics, empty to disable"`
+}
+
+func (m *Metrics) Validate() error {
+	if m.Listen == "" {
+		return nil
+	}
+	return ValidateAddress("metrics.listen", m.Listen)
+}
+
+// Tracing selects where a binary sends its OpenTelemetry spans.
+type Tracing struct {
+	// Exporter is tracing.ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter string `yaml:"exporter" flag:"tracing-exporter" usage:"where to send trace spans: none, stdout or otlp"`
+	Endpoint string `yaml:"endpoint" flag:"tracing-endpoint" usage:"OTLP/gRPC collector URL; http:// sends spans in plaintext"`
+}
+
+func (t *Tracing) Validate() error {
+	switch t.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+		return nil
+	case tracing.ExporterOTLP:
+		if _, err := url.Parse(t.Endpoint); err != nil || t.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint must be a URL, got %q", t.Endpoint)
+		}
+		return nil
+	default:
+		return fmt.Errorf("tracing.exporter must be %s, %s or %s, got %q",
+			tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP, t.Exporter)
+	}
+}
+
+// Logging holds the level below which log lines are dropped.
+type Logging struct {
+	Level string `yaml:"level" flag:"log-level" usage:"lowest level logged: debug, info, warn or error"`
+}
+
+// SlogLevel returns Level as a slog.Level. Validate has checked it parses.
+func (l *Logging) SlogLevel() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(l.Level))
+	return level
+}
+
+func (l *Logging) Validate() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return fmt.Errorf("logging.level must be debug, info, warn or error, got %q", l.Level)
+	}
+	return nil
+}
+
+// Timeouts holds the timeouts every binary shares.
+type Timeouts struct {
+	Shutdown time.Duration `yaml:"shutdown" flag:"shutdown-timeout" usage:"how long to wait for in-flight requests to finish on shutdown"`
+}
+
+func (t *Timeouts) Validate() error {
+	if t.Shutdown <= 0 {
+		return errors.New("timeouts.shutdown must be positive")
+	}
+	return nil
+}
+
+// ValidateAddress checks that addr is a host:port pair, naming the setting in
+// the error.
+func ValidateAddress(name, addr string) error {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
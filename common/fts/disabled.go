@@ -0,0 +1,6 @@
+//go:build !(sqlite_fts5 || fts5)
+
+package fts
+
+// Enabled reports whether the SQLite driver was built with FTS5.
+const Enabled = false
@@ -0,0 +1,37 @@
+// Package fts holds the pieces of full-text search shared by the racing and
+// sports services: whether the SQLite driver was built with FTS5, and turning
+// user input into a safe FTS5 MATCH expression.
+//
+// The bundled go-sqlite3 driver only compiles FTS5 with the sqlite_fts5 build
+// tag. The services index and rank with FTS5 and its bm25() function, so they
+// must be built and tested with -tags sqlite_fts5 for search to work.
+package fts
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrUnavailable is returned by searches in a build without FTS5.
+var ErrUnavailable = errors.New("full-text search needs a build with -tags sqlite_fts5")
+
+// MatchQuery turns free text into an FTS5 MATCH expression in which every term
+// must appear. The last term is matched as a prefix so partially typed words
+// still find results. Each term is quoted and punctuation in the input is
+// dropped, so user input can never produce an invalid expression. It returns
+// "" when the input holds no searchable terms.
+func MatchQuery(input string) string {
+	terms := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return ""
+	}
+
+	for i, term := range terms {
+		terms[i] = `"` + term + `"`
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
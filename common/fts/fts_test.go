@@ -0,0 +1,27 @@
+package fts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: ""},
+		{input: "  -- ", want: ""},
+		{input: "haw", want: `"haw"*`},
+		{input: "Red Haw", want: `"red" "haw"*`},
+		{input: `red" OR name:*`, want: `"red" "or" "name"*`},
+		{input: "Café NEAR", want: `"café" "near"*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchQuery(tt.input))
+		})
+	}
+}
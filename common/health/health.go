@@ -0,0 +1,140 @@
+// Package health publishes a service's readiness over the standard
+// grpc.health.v1 protocol, driven by a check against a real dependency such as
+// the database.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultInterval is how often Run re-checks the dependency.
+	DefaultInterval = 5 * time.Second
+
+	// checkTimeout bounds a single dependency check.
+	checkTimeout = time.Second
+)
+
+// Check reports whether a dependency the service needs is usable.
+type Check func(ctx context.Context) error
+
+// Reporter keeps the health status of a gRPC server in step with a Check.
+//
+// The status is reported for the server as a whole (the empty service name) and
+// for each named service, so probes may ask about either.
+type Reporter struct {
+	server       *grpchealth.Server
+	check        Check
+	services     []string
+	stopping     chan struct{}
+	stopWatching sync.Once
+}
+
+// Register adds the grpc.health.v1 service to s and returns a Reporter for it.
+// Every service starts NOT_SERVING until the first check passes.
+func Register(s *grpc.Server, check Check, services ...string) *Reporter {
+	r := &Reporter{
+		server:   grpchealth.NewServer(),
+		check:    check,
+		services: append([]string{""}, services...),
+		stopping: make(chan struct{}),
+	}
+	r.set(healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, &healthServer{Server: r.server, stopping: r.stopping})
+	return r
+}
+
+// Run checks the dependency straight away and then every interval until ctx is
+// done.
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.update(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown reports every service as NOT_SERVING and ignores later checks, so
+// load balancers stop sending traffic while the server drains. Open Watch
+// streams are sent NOT_SERVING and then ended, as a graceful stop would
+// otherwise wait on them until it timed out.
+func (r *Reporter) Shutdown() {
+	r.server.Shutdown()
+	r.stopWatching.Do(func() { close(r.stopping) })
+}
+
+// update runs the check once and publishes the result.
+func (r *Reporter) update(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err := r.check(ctx); err != nil {
+		slog.Warn("health check failed", "error", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	r.set(status)
+}
+
+func (r *Reporter) set(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range r.services {
+		r.server.SetServingStatus(service, status)
+	}
+}
+
+// healthServer is grpc-go's health server with Watch streams that end once
+// stopping is closed.
+type healthServer struct {
+	*grpchealth.Server
+	stopping <-chan struct{}
+}
+
+func (h *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-h.stopping:
+			close(stopped)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := h.Server.Watch(req, &watchStream{Health_WatchServer: stream, ctx: ctx})
+	select {
+	case <-stopped:
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return err
+	}
+}
+
+// watchStream is a Watch stream whose context ends early when the server shuts
+// down.
+type watchStream struct {
+	healthpb.Health_WatchServer
+	ctx context.Context
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
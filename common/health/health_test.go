@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/shutdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestReporter(t *testing.T) {
+	var checkErr error
+	r := Register(grpc.NewServer(), func(context.Context) error { return checkErr }, "racing.Racing")
+
+	status := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := r.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if !assert.NoError(t, err) {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+
+	// Nothing is served until the first check passes.
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""))
+
+	r.update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status("racing.Racing"))
+
+	checkErr = errors.New("database is locked")
+	r.update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status("racing.Racing"))
+
+	// After shutdown a passing check no longer brings the service back.
+	checkErr = nil
+	r.Shutdown()
+	r.update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(""))
+}
+
+func TestReporter_ShutdownEndsWatch(t *testing.T) {
+	srv := grpc.NewServer()
+	r := Register(srv, func(context.Context) error { return nil })
+	r.update(context.Background())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(ln)
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	watch, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	// An open Watch stream does not hold up draining: the watcher hears
+	// NOT_SERVING and its stream ends.
+	r.Shutdown()
+	begun := time.Now()
+	assert.NoError(t, shutdown.GRPC(srv, 5*time.Second))
+	assert.Less(t, time.Since(begun), time.Second)
+
+	resp, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
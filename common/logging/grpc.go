@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxFilterLen bounds the filter summary in an access log line.
+const maxFilterLen = 256
+
+// UnaryServerInterceptor takes the request id from the x-request-id metadata,
+// or generates one, puts it in the context and returns it in the response
+// headers. When the RPC completes it writes an access log line to logger with
+// the method, duration, status code and, for requests with a filter field, a
+// summary of the filter.
+//
+// Install it first, so everything after it logs with the request id.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		id := incomingRequestID(ctx)
+		ctx = NewContext(ctx, id)
+		// Fails only outside a real server, as in tests.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("code", code.String()),
+		}
+		if filter, ok := filterSummary(req); ok {
+			attrs = append(attrs, slog.String("filter", filter))
+		}
+		logger.LogAttrs(ctx, levelOf(code), "rpc", attrs...)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs. The
+// access log line is written when the stream ends; it has no filter summary,
+// since a stream has no single request.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		id := incomingRequestID(ss.Context())
+		ctx := NewContext(ss.Context(), id)
+		_ = ss.SetHeader(metadata.Pairs(RequestIDMetadata, id))
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		code := status.Code(err)
+		logger.LogAttrs(ctx, levelOf(code), "rpc",
+			slog.String("method", info.FullMethod),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("code", code.String()),
+		)
+
+		return err
+	}
+}
+
+// serverStream is a grpc.ServerStream with its context replaced.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// incomingRequestID returns the request id in the x-request-id metadata, or a
+// new one when there is none or it is unsafe to log.
+func incomingRequestID(ctx context.Context) string {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDMetadata); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if !ValidRequestID(id) {
+		id = NewRequestID()
+	}
+	return id
+}
+
+// levelOf logs server faults as errors and everything else, including caller
+// mistakes, as info.
+func levelOf(code codes.Code) slog.Level {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// filterSummary renders the filter field of req, if it has one set, as compact
+// JSON of at most maxFilterLen bytes.
+func filterSummary(req any) (string, bool) {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return "", false
+	}
+	msg := m.ProtoReflect()
+	fd := msg.Descriptor().Fields().ByName("filter")
+	if fd == nil || fd.Message() == nil || !msg.Has(fd) {
+		return "", false
+	}
+
+	b, err := protojson.Marshal(msg.Get(fd).Message().Interface())
+	if err != nil {
+		return "", false
+	}
+	// protojson varies its spacing on purpose; make it stable.
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return "", false
+	}
+
+	s := buf.String()
+	if len(s) > maxFilterLen {
+		s = s[:maxFilterLen] + "…"
+	}
+	return s, true
+}
@@ -0,0 +1,89 @@
+// Package logging provides structured JSON logging with request ids.
+//
+// The gateway gives every request an id, taken from the caller's X-Request-Id
+// header or generated, and passes it to the backends as x-request-id metadata.
+// Loggers built with New add that id to every line logged with the request's
+// context.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+)
+
+const (
+	// RequestIDHeader carries the request id over HTTP, both ways.
+	RequestIDHeader = "X-Request-Id"
+	// RequestIDMetadata carries the request id over gRPC, both ways.
+	RequestIDMetadata = "x-request-id"
+
+	// maxRequestIDLen bounds a caller-supplied request id.
+	maxRequestIDLen = 128
+)
+
+// New returns a logger writing JSON lines to w at level and above. Lines
+// logged with a context holding a request id carry it as request_id.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// contextHandler adds the request id in the record's context to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// NewContext returns a copy of ctx carrying the request id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id in ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random request id.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether a caller-supplied id is safe to adopt: at most
+// 128 letters, digits, '-', '_', '.' or ':'. Anything else is replaced, so
+// callers cannot inject text into the logs.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestNew_AddsRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, slog.LevelInfo)
+
+	logger.DebugContext(NewContext(context.Background(), "abc"), "hidden")
+	logger.InfoContext(NewContext(context.Background(), "abc"), "hello", "n", 1)
+	logger.With("service", "racing").Info("no request")
+
+	lines := decodeLines(t, &out)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "hello", lines[0]["msg"])
+	assert.Equal(t, "abc", lines[0]["request_id"])
+	assert.NotContains(t, lines[1], "request_id")
+	assert.Equal(t, "racing", lines[1]["service"])
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("4bf92f35-77b3:4da6_a3ce.929d"))
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("evil\n{\"level\":\"ERROR\"}"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", 129)))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var out bytes.Buffer
+	intercept := UnaryServerInterceptor(New(&out, slog.LevelInfo))
+	info := &grpc.UnaryServerInfo{FullMethod: "/racing.Racing/ListRaces"}
+
+	var seen []string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		seen = append(seen, RequestID(ctx))
+		return nil, status.Error(codes.NotFound, "no race")
+	}
+
+	// An id from the gateway is adopted.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadata, "from-gateway"))
+	_, err := intercept(ctx, listRequest(t), info, handler)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Without one, or with an unsafe one, a fresh id is made.
+	_, _ = intercept(context.Background(), &emptypb.Empty{}, info, handler)
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadata, "bad id"))
+	_, _ = intercept(ctx, &emptypb.Empty{}, info, handler)
+
+	require.Len(t, seen, 3)
+	assert.Equal(t, "from-gateway", seen[0])
+	assert.Len(t, seen[1], 32)
+	assert.Len(t, seen[2], 32)
+
+	lines := decodeLines(t, &out)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "rpc", lines[0]["msg"])
+	assert.Equal(t, "INFO", lines[0]["level"])
+	assert.Equal(t, "/racing.Racing/ListRaces", lines[0]["method"])
+	assert.Equal(t, "NotFound", lines[0]["code"])
+	assert.Equal(t, "from-gateway", lines[0]["request_id"])
+	assert.Equal(t, `{"meetingIds":["1","2"],"onlyVisible":true}`, lines[0]["filter"])
+	assert.Contains(t, lines[0], "duration_ms")
+	assert.NotContains(t, lines[1], "filter")
+	assert.Equal(t, seen[1], lines[1]["request_id"])
+}
+
+func TestLevelOf(t *testing.T) {
+	assert.Equal(t, slog.LevelInfo, levelOf(codes.OK))
+	assert.Equal(t, slog.LevelInfo, levelOf(codes.InvalidArgument))
+	assert.Equal(t, slog.LevelError, levelOf(codes.Internal))
+}
+
+// listRequest builds a message shaped like racing's ListRacesRequest, with a
+// filter of meeting ids 1 and 2, visible only.
+func listRequest(t *testing.T) proto.Message {
+	t.Helper()
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("logtest.proto"),
+		Package: proto.String("logtest"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Filter"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("meeting_ids"), JsonName: proto.String("meetingIds"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+					{Name: proto.String("only_visible"), JsonName: proto.String("onlyVisible"), Number: proto.Int32(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()},
+				},
+			},
+			{
+				Name: proto.String("ListRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("filter"), JsonName: proto.String("filter"), Number: proto.Int32(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".logtest.Filter")},
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	filterDesc := file.Messages().ByName("Filter")
+	filter := dynamicpb.NewMessage(filterDesc)
+	ids := filter.Mutable(filterDesc.Fields().ByName("meeting_ids")).List()
+	ids.Append(protoreflect.ValueOfInt64(1))
+	ids.Append(protoreflect.ValueOfInt64(2))
+	filter.Set(filterDesc.Fields().ByName("only_visible"), protoreflect.ValueOfBool(true))
+
+	reqDesc := file.Messages().ByName("ListRequest")
+	req := dynamicpb.NewMessage(reqDesc)
+	req.Set(reqDesc.Fields().ByName("filter"), protoreflect.ValueOfMessage(filter))
+	return req
+}
+
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var line map[string]any
+		require.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fakeStream is a grpc.ServerStream that records the headers it is sent.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	var out bytes.Buffer
+	intercept := StreamServerInterceptor(New(&out, slog.LevelInfo))
+	info := &grpc.StreamServerInfo{FullMethod: "/racing.Racing/ImportRaces", IsClientStream: true}
+
+	var seen string
+	ss := &fakeStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadata, "from-cli"))}
+	err := intercept(nil, ss, info, func(_ any, ss grpc.ServerStream) error {
+		seen = RequestID(ss.Context())
+		return status.Error(codes.Internal, "disk full")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "from-cli", seen)
+	assert.Equal(t, []string{"from-cli"}, ss.header.Get(RequestIDMetadata))
+
+	lines := decodeLines(t, &out)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "ERROR", lines[0]["level"])
+	assert.Equal(t, "/racing.Racing/ImportRaces", lines[0]["method"])
+	assert.Equal(t, "Internal", lines[0]["code"])
+	assert.Equal(t, "from-cli", lines[0]["request_id"])
+	assert.Contains(t, lines[0], "duration_ms")
+}
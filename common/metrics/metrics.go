@@ -0,0 +1,133 @@
+// Package metrics exposes Prometheus metrics for the gateway and the gRPC
+// services.
+//
+// Each binary builds its own registry with NewRegistry, registers the metrics
+// it records, and serves them with Handler on /metrics.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// NewRegistry returns a registry holding the Go runtime and process collectors.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler serves the metrics in reg.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
+
+// NewServer returns an HTTP server for addr that serves reg on /metrics.
+func NewServer(addr string, reg *prometheus.Registry) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(reg))
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// GRPCServer records the RPCs a gRPC server handles.
+type GRPCServer struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewGRPCServer registers the gRPC server metrics with reg:
+//
+//   - grpc_server_handled_total{grpc_service, grpc_method, grpc_code}
+//   - grpc_server_handling_seconds{grpc_service, grpc_method}
+func NewGRPCServer(reg prometheus.Registerer) *GRPCServer {
+	m := &GRPCServer{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by method and status code.",
+		}, []string{"grpc_service", "grpc_method", "grpc_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken to handle an RPC, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_service", "grpc_method"}),
+	}
+	reg.MustRegister(m.handled, m.duration)
+	return m
+}
+
+// UnaryServerInterceptor records every unary RPC. Install it first, so RPCs
+// refused by later interceptors are counted too.
+func (m *GRPCServer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records every streaming RPC, timed from when the
+// stream opens until the handler returns.
+func (m *GRPCServer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, start)
+		return err
+	}
+}
+
+// observe records an RPC to fullMethod that started at start and ended with err.
+func (m *GRPCServer) observe(fullMethod string, err error, start time.Time) {
+	service, method := splitMethod(fullMethod)
+	m.handled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+	m.duration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+}
+
+// QueryTimer records how long repository queries take.
+type QueryTimer struct {
+	duration *prometheus.HistogramVec
+}
+
+// NewQueryTimer registers <namespace>_db_query_duration_seconds{query} with reg.
+func NewQueryTimer(reg prometheus.Registerer, namespace string) *QueryTimer {
+	t := &QueryTimer{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Time taken by repository queries, by query type.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"query"}),
+	}
+	reg.MustRegister(t.duration)
+	return t
+}
+
+// Observe records that a query of the given type took d.
+func (t *QueryTimer) Observe(query string, d time.Duration) {
+	t.duration.WithLabelValues(query).Observe(d.Seconds())
+}
+
+// splitMethod splits "/package.Service/Method" into service and method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewGRPCServer(reg)
+	intercept := m.UnaryServerInterceptor()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/racing.Racing/GetRace"}
+	ok := func(context.Context, any) (any, error) { return "race", nil }
+	notFound := func(context.Context, any) (any, error) { return nil, status.Error(codes.NotFound, "no race") }
+
+	for range 2 {
+		resp, err := intercept(context.Background(), nil, info, ok)
+		require.NoError(t, err)
+		assert.Equal(t, "race", resp)
+	}
+	_, err := intercept(context.Background(), nil, info, notFound)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.handled.WithLabelValues("racing.Racing", "GetRace", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.handled.WithLabelValues("racing.Racing", "GetRace", "NotFound")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
+
+func TestQueryTimer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	qt := NewQueryTimer(reg, "racing")
+	qt.Observe("list", 3*time.Millisecond)
+	qt.Observe("get", time.Millisecond)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(reg, "racing_db_query_duration_seconds"))
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	NewQueryTimer(reg, "racing").Observe("list", time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.True(t, strings.Contains(body, `racing_db_query_duration_seconds_count{query="list"} 1`), body)
+	assert.True(t, strings.Contains(body, "go_goroutines"), body)
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/grpc.health.v1.Health/Check")
+	assert.Equal(t, "grpc.health.v1.Health", service)
+	assert.Equal(t, "Check", method)
+
+	service, method = splitMethod("bogus")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "unknown", method)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewGRPCServer(reg)
+	intercept := m.StreamServerInterceptor()
+
+	info := &grpc.StreamServerInfo{FullMethod: "/racing.Racing/ImportRaces", IsClientStream: true}
+	require.NoError(t, intercept(nil, nil, info, func(any, grpc.ServerStream) error { return nil }))
+	err := intercept(nil, nil, info, func(any, grpc.ServerStream) error {
+		return status.Error(codes.PermissionDenied, "needs trader")
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.handled.WithLabelValues("racing.Racing", "ImportRaces", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.handled.WithLabelValues("racing.Racing", "ImportRaces", "PermissionDenied")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
@@ -0,0 +1,4 @@
+package proto
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative validate/validate.proto
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative racing/racing.proto
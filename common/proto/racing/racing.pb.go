@@ -0,0 +1,2446 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: racing/racing.proto
+
+package racing
+
+import (
+	_ "github.com/SylvanSol/Entain_Test/common/proto/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Derive this based on advertised_start_time (past ⇒ CLOSED, future ⇒ OPEN).
+// An abandoned race is always ABANDONED, and a delayed race that has not yet
+// jumped is DELAYED.
+type RaceStatus int32
+
+const (
+	RaceStatus_UNSPECIFIED RaceStatus = 0
+	RaceStatus_OPEN        RaceStatus = 1
+	RaceStatus_CLOSED      RaceStatus = 2
+	RaceStatus_ABANDONED   RaceStatus = 3
+	RaceStatus_DELAYED     RaceStatus = 4
+)
+
+// Enum value maps for RaceStatus.
+var (
+	RaceStatus_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "OPEN",
+		2: "CLOSED",
+		3: "ABANDONED",
+		4: "DELAYED",
+	}
+	RaceStatus_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"OPEN":        1,
+		"CLOSED":      2,
+		"ABANDONED":   3,
+		"DELAYED":     4,
+	}
+)
+
+func (x RaceStatus) Enum() *RaceStatus {
+	p := new(RaceStatus)
+	*p = x
+	return p
+}
+
+func (x RaceStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RaceStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_racing_racing_proto_enumTypes[0].Descriptor()
+}
+
+func (RaceStatus) Type() protoreflect.EnumType {
+	return &file_racing_racing_proto_enumTypes[0]
+}
+
+func (x RaceStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RaceStatus.Descriptor instead.
+func (RaceStatus) EnumDescriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{0}
+}
+
+// What an import did to a meeting or race.
+type ImportAction int32
+
+const (
+	ImportAction_IMPORT_ACTION_UNSPECIFIED ImportAction = 0
+	ImportAction_IMPORT_ACTION_CREATE      ImportAction = 1
+	ImportAction_IMPORT_ACTION_UPDATE      ImportAction = 2
+)
+
+// Enum value maps for ImportAction.
+var (
+	ImportAction_name = map[int32]string{
+		0: "IMPORT_ACTION_UNSPECIFIED",
+		1: "IMPORT_ACTION_CREATE",
+		2: "IMPORT_ACTION_UPDATE",
+	}
+	ImportAction_value = map[string]int32{
+		"IMPORT_ACTION_UNSPECIFIED": 0,
+		"IMPORT_ACTION_CREATE":      1,
+		"IMPORT_ACTION_UPDATE":      2,
+	}
+)
+
+func (x ImportAction) Enum() *ImportAction {
+	p := new(ImportAction)
+	*p = x
+	return p
+}
+
+func (x ImportAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_racing_racing_proto_enumTypes[1].Descriptor()
+}
+
+func (ImportAction) Type() protoreflect.EnumType {
+	return &file_racing_racing_proto_enumTypes[1]
+}
+
+func (x ImportAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportAction.Descriptor instead.
+func (ImportAction) EnumDescriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{1}
+}
+
+// Kind of change recorded in a race's schedule history.
+type ScheduleChangeType int32
+
+const (
+	ScheduleChangeType_SCHEDULE_CHANGE_UNSPECIFIED ScheduleChangeType = 0
+	ScheduleChangeType_SCHEDULE_CHANGE_DELAY       ScheduleChangeType = 1
+	ScheduleChangeType_SCHEDULE_CHANGE_ABANDON     ScheduleChangeType = 2
+	ScheduleChangeType_SCHEDULE_CHANGE_REINSTATE   ScheduleChangeType = 3
+	// The provider's feed moved the start time, through ImportRaces.
+	ScheduleChangeType_SCHEDULE_CHANGE_IMPORT ScheduleChangeType = 4
+)
+
+// Enum value maps for ScheduleChangeType.
+var (
+	ScheduleChangeType_name = map[int32]string{
+		0: "SCHEDULE_CHANGE_UNSPECIFIED",
+		1: "SCHEDULE_CHANGE_DELAY",
+		2: "SCHEDULE_CHANGE_ABANDON",
+		3: "SCHEDULE_CHANGE_REINSTATE",
+		4: "SCHEDULE_CHANGE_IMPORT",
+	}
+	ScheduleChangeType_value = map[string]int32{
+		"SCHEDULE_CHANGE_UNSPECIFIED": 0,
+		"SCHEDULE_CHANGE_DELAY":       1,
+		"SCHEDULE_CHANGE_ABANDON":     2,
+		"SCHEDULE_CHANGE_REINSTATE":   3,
+		"SCHEDULE_CHANGE_IMPORT":      4,
+	}
+)
+
+func (x ScheduleChangeType) Enum() *ScheduleChangeType {
+	p := new(ScheduleChangeType)
+	*p = x
+	return p
+}
+
+func (x ScheduleChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduleChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_racing_racing_proto_enumTypes[2].Descriptor()
+}
+
+func (ScheduleChangeType) Type() protoreflect.EnumType {
+	return &file_racing_racing_proto_enumTypes[2]
+}
+
+func (x ScheduleChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduleChangeType.Descriptor instead.
+func (ScheduleChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{2}
+}
+
+// Request for ListRaces call.
+type ListRacesRequest struct {
+	state  protoimpl.MessageState  `protogen:"open.v1"`
+	Filter *ListRacesRequestFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Deprecated: use sorts. Still honoured when sorts is empty.
+	Sort *Sort `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Sorts orders races by each field in turn, e.g. meeting_id then number.
+	// Races are always finally ordered by id so results are deterministic.
+	Sorts []*Sort `protobuf:"bytes,3,rep,name=sorts,proto3" json:"sorts,omitempty"`
+	// ReadMask names the Race fields to return, e.g. "id,name". Every field is
+	// returned when it is unset or "*".
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// If true the response includes counts of every race matching the filter.
+	IncludeFacets bool `protobuf:"varint,5,opt,name=include_facets,json=includeFacets,proto3" json:"include_facets,omitempty"`
+	// PageSize is the most races to return. Zero returns every race matching
+	// the filter in one response.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// PageToken is the next_page_token of the previous page. The rest of the
+	// request must be the same as for that page.
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRacesRequest) Reset() {
+	*x = ListRacesRequest{}
+	mi := &file_racing_racing_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRacesRequest) ProtoMessage() {}
+
+func (x *ListRacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRacesRequest.ProtoReflect.Descriptor instead.
+func (*ListRacesRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListRacesRequest) GetFilter() *ListRacesRequestFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListRacesRequest) GetSort() *Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return nil
+}
+
+func (x *ListRacesRequest) GetSorts() []*Sort {
+	if x != nil {
+		return x.Sorts
+	}
+	return nil
+}
+
+func (x *ListRacesRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListRacesRequest) GetIncludeFacets() bool {
+	if x != nil {
+		return x.IncludeFacets
+	}
+	return false
+}
+
+func (x *ListRacesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRacesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// Response to ListRaces call.
+type ListRacesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Races []*Race                `protobuf:"bytes,1,rep,name=races,proto3" json:"races,omitempty"`
+	// Counts of the races matching the filter. Only set when requested.
+	Facets *RaceFacets `protobuf:"bytes,2,opt,name=facets,proto3" json:"facets,omitempty"`
+	// NextPageToken fetches the next page when sent as page_token. It is empty
+	// on the last page, and when page_size was not set. The next page starts
+	// after this page's last race in the list's order, so races added, removed
+	// or moved between pages are neither skipped nor repeated.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRacesResponse) Reset() {
+	*x = ListRacesResponse{}
+	mi := &file_racing_racing_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRacesResponse) ProtoMessage() {}
+
+func (x *ListRacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRacesResponse.ProtoReflect.Descriptor instead.
+func (*ListRacesResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListRacesResponse) GetRaces() []*Race {
+	if x != nil {
+		return x.Races
+	}
+	return nil
+}
+
+func (x *ListRacesResponse) GetFacets() *RaceFacets {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *ListRacesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Counts of the races matching a filter, in total and grouped.
+type RaceFacets struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// TotalCount is the number of races matching the filter.
+	TotalCount int64 `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// Meetings counts the races in each meeting, by meeting ID.
+	Meetings []*MeetingCount `protobuf:"bytes,2,rep,name=meetings,proto3" json:"meetings,omitempty"`
+	// Statuses counts the races in each status.
+	Statuses []*StatusCount `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	// VisibleCount is the number of races that are visible.
+	VisibleCount int64 `protobuf:"varint,4,opt,name=visible_count,json=visibleCount,proto3" json:"visible_count,omitempty"`
+	// HiddenCount is the number of races that are hidden.
+	HiddenCount   int64 `protobuf:"varint,5,opt,name=hidden_count,json=hiddenCount,proto3" json:"hidden_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RaceFacets) Reset() {
+	*x = RaceFacets{}
+	mi := &file_racing_racing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RaceFacets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaceFacets) ProtoMessage() {}
+
+func (x *RaceFacets) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaceFacets.ProtoReflect.Descriptor instead.
+func (*RaceFacets) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RaceFacets) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *RaceFacets) GetMeetings() []*MeetingCount {
+	if x != nil {
+		return x.Meetings
+	}
+	return nil
+}
+
+func (x *RaceFacets) GetStatuses() []*StatusCount {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *RaceFacets) GetVisibleCount() int64 {
+	if x != nil {
+		return x.VisibleCount
+	}
+	return 0
+}
+
+func (x *RaceFacets) GetHiddenCount() int64 {
+	if x != nil {
+		return x.HiddenCount
+	}
+	return 0
+}
+
+// Number of races in a meeting.
+type MeetingCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MeetingId     int64                  `protobuf:"varint,1,opt,name=meeting_id,json=meetingId,proto3" json:"meeting_id,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MeetingCount) Reset() {
+	*x = MeetingCount{}
+	mi := &file_racing_racing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MeetingCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MeetingCount) ProtoMessage() {}
+
+func (x *MeetingCount) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MeetingCount.ProtoReflect.Descriptor instead.
+func (*MeetingCount) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MeetingCount) GetMeetingId() int64 {
+	if x != nil {
+		return x.MeetingId
+	}
+	return 0
+}
+
+func (x *MeetingCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Number of races in a status.
+type StatusCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        RaceStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=racing.RaceStatus" json:"status,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusCount) Reset() {
+	*x = StatusCount{}
+	mi := &file_racing_racing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusCount) ProtoMessage() {}
+
+func (x *StatusCount) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusCount.ProtoReflect.Descriptor instead.
+func (*StatusCount) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StatusCount) GetStatus() RaceStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RaceStatus_UNSPECIFIED
+}
+
+func (x *StatusCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Filter for listing races.
+type ListRacesRequestFilter struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	MeetingIds []int64                `protobuf:"varint,1,rep,packed,name=meeting_ids,json=meetingIds,proto3" json:"meeting_ids,omitempty"`
+	// Add Visibility Filter
+	OnlyVisible   bool `protobuf:"varint,2,opt,name=only_visible,json=onlyVisible,proto3" json:"only_visible,omitempty"` // If true only returns races where visible = true
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRacesRequestFilter) Reset() {
+	*x = ListRacesRequestFilter{}
+	mi := &file_racing_racing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRacesRequestFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRacesRequestFilter) ProtoMessage() {}
+
+func (x *ListRacesRequestFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRacesRequestFilter.ProtoReflect.Descriptor instead.
+func (*ListRacesRequestFilter) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRacesRequestFilter) GetMeetingIds() []int64 {
+	if x != nil {
+		return x.MeetingIds
+	}
+	return nil
+}
+
+func (x *ListRacesRequestFilter) GetOnlyVisible() bool {
+	if x != nil {
+		return x.OnlyVisible
+	}
+	return false
+}
+
+// Filter for listing races.
+type Sort struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`         //e.g "name", "number"
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"` //e.g "asc" or "desc"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sort) Reset() {
+	*x = Sort{}
+	mi := &file_racing_racing_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sort) ProtoMessage() {}
+
+func (x *Sort) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sort.ProtoReflect.Descriptor instead.
+func (*Sort) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Sort) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Sort) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+// A race resource.
+type Race struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID represents a unique identifier for the race.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// MeetingID represents a unique identifier for the races meeting.
+	MeetingId int64 `protobuf:"varint,2,opt,name=meeting_id,json=meetingId,proto3" json:"meeting_id,omitempty"`
+	// Name is the official name given to the race.
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Number represents the number of the race.
+	Number int64 `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
+	// Visible represents whether or not the race is visible.
+	Visible bool `protobuf:"varint,5,opt,name=visible,proto3" json:"visible,omitempty"`
+	// AdvertisedStartTime is the time the race is advertised to run.
+	AdvertisedStartTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=advertised_start_time,json=advertisedStartTime,proto3" json:"advertised_start_time,omitempty"`
+	// Status of Race
+	Status        RaceStatus `protobuf:"varint,7,opt,name=status,proto3,enum=racing.RaceStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Race) Reset() {
+	*x = Race{}
+	mi := &file_racing_racing_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Race) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Race) ProtoMessage() {}
+
+func (x *Race) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Race.ProtoReflect.Descriptor instead.
+func (*Race) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Race) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Race) GetMeetingId() int64 {
+	if x != nil {
+		return x.MeetingId
+	}
+	return 0
+}
+
+func (x *Race) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Race) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Race) GetVisible() bool {
+	if x != nil {
+		return x.Visible
+	}
+	return false
+}
+
+func (x *Race) GetAdvertisedStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AdvertisedStartTime
+	}
+	return nil
+}
+
+func (x *Race) GetStatus() RaceStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RaceStatus_UNSPECIFIED
+}
+
+// A single change made to a race's schedule.
+type ScheduleChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID represents a unique identifier for the change.
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// RaceID is the race the change was made to.
+	RaceId int64 `protobuf:"varint,2,opt,name=race_id,json=raceId,proto3" json:"race_id,omitempty"`
+	// Type is the kind of change made.
+	Type ScheduleChangeType `protobuf:"varint,3,opt,name=type,proto3,enum=racing.ScheduleChangeType" json:"type,omitempty"`
+	// PreviousStartTime is the advertised start time before the change.
+	PreviousStartTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=previous_start_time,json=previousStartTime,proto3" json:"previous_start_time,omitempty"`
+	// NewStartTime is the advertised start time after the change.
+	NewStartTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=new_start_time,json=newStartTime,proto3" json:"new_start_time,omitempty"`
+	// Reason is the explanation given for the change.
+	Reason string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	// ChangedAt is when the change was made.
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleChange) Reset() {
+	*x = ScheduleChange{}
+	mi := &file_racing_racing_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleChange) ProtoMessage() {}
+
+func (x *ScheduleChange) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleChange.ProtoReflect.Descriptor instead.
+func (*ScheduleChange) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ScheduleChange) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduleChange) GetRaceId() int64 {
+	if x != nil {
+		return x.RaceId
+	}
+	return 0
+}
+
+func (x *ScheduleChange) GetType() ScheduleChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ScheduleChangeType_SCHEDULE_CHANGE_UNSPECIFIED
+}
+
+func (x *ScheduleChange) GetPreviousStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreviousStartTime
+	}
+	return nil
+}
+
+func (x *ScheduleChange) GetNewStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NewStartTime
+	}
+	return nil
+}
+
+func (x *ScheduleChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ScheduleChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+// Request for GetRace call.
+type GetRaceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// If true the response includes the race's schedule history.
+	IncludeHistory bool `protobuf:"varint,2,opt,name=include_history,json=includeHistory,proto3" json:"include_history,omitempty"`
+	// ReadMask names the Race fields to return, as for ListRaces.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRaceRequest) Reset() {
+	*x = GetRaceRequest{}
+	mi := &file_racing_racing_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRaceRequest) ProtoMessage() {}
+
+func (x *GetRaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRaceRequest.ProtoReflect.Descriptor instead.
+func (*GetRaceRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRaceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetRaceRequest) GetIncludeHistory() bool {
+	if x != nil {
+		return x.IncludeHistory
+	}
+	return false
+}
+
+func (x *GetRaceRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+// Response to GetRace call.
+type GetRaceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Race  *Race                  `protobuf:"bytes,1,opt,name=race,proto3" json:"race,omitempty"`
+	// History of schedule changes, oldest first. Only set when requested.
+	History []*ScheduleChange `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"`
+	// OriginalStartTime is the start time before any delays. Only set when
+	// history is requested.
+	OriginalStartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=original_start_time,json=originalStartTime,proto3" json:"original_start_time,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetRaceResponse) Reset() {
+	*x = GetRaceResponse{}
+	mi := &file_racing_racing_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRaceResponse) ProtoMessage() {}
+
+func (x *GetRaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRaceResponse.ProtoReflect.Descriptor instead.
+func (*GetRaceResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetRaceResponse) GetRace() *Race {
+	if x != nil {
+		return x.Race
+	}
+	return nil
+}
+
+func (x *GetRaceResponse) GetHistory() []*ScheduleChange {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *GetRaceResponse) GetOriginalStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OriginalStartTime
+	}
+	return nil
+}
+
+// Request for BatchGetRaces call.
+type BatchGetRacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IDs of the races to return. An ID asked for more than once is returned once.
+	Ids []int64 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	// ReadMask names the Race fields to return, as for ListRaces.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRacesRequest) Reset() {
+	*x = BatchGetRacesRequest{}
+	mi := &file_racing_racing_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRacesRequest) ProtoMessage() {}
+
+func (x *BatchGetRacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRacesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRacesRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetRacesRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchGetRacesRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+// Response to BatchGetRaces call.
+type BatchGetRacesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Races found, in the order their IDs were asked for.
+	Races []*Race `protobuf:"bytes,1,rep,name=races,proto3" json:"races,omitempty"`
+	// NotFound lists the IDs asked for that match no race, in the order they
+	// were asked for.
+	NotFound      []int64 `protobuf:"varint,2,rep,packed,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRacesResponse) Reset() {
+	*x = BatchGetRacesResponse{}
+	mi := &file_racing_racing_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRacesResponse) ProtoMessage() {}
+
+func (x *BatchGetRacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRacesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetRacesResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchGetRacesResponse) GetRaces() []*Race {
+	if x != nil {
+		return x.Races
+	}
+	return nil
+}
+
+func (x *BatchGetRacesResponse) GetNotFound() []int64 {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+// Request for DelayRace call.
+type DelayRaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NewStartTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=new_start_time,json=newStartTime,proto3" json:"new_start_time,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelayRaceRequest) Reset() {
+	*x = DelayRaceRequest{}
+	mi := &file_racing_racing_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelayRaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelayRaceRequest) ProtoMessage() {}
+
+func (x *DelayRaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelayRaceRequest.ProtoReflect.Descriptor instead.
+func (*DelayRaceRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DelayRaceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DelayRaceRequest) GetNewStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NewStartTime
+	}
+	return nil
+}
+
+func (x *DelayRaceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Response to DelayRace call.
+type DelayRaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Race          *Race                  `protobuf:"bytes,1,opt,name=race,proto3" json:"race,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelayRaceResponse) Reset() {
+	*x = DelayRaceResponse{}
+	mi := &file_racing_racing_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelayRaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelayRaceResponse) ProtoMessage() {}
+
+func (x *DelayRaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelayRaceResponse.ProtoReflect.Descriptor instead.
+func (*DelayRaceResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DelayRaceResponse) GetRace() *Race {
+	if x != nil {
+		return x.Race
+	}
+	return nil
+}
+
+// Request for AbandonRace call.
+type AbandonRaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbandonRaceRequest) Reset() {
+	*x = AbandonRaceRequest{}
+	mi := &file_racing_racing_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbandonRaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbandonRaceRequest) ProtoMessage() {}
+
+func (x *AbandonRaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbandonRaceRequest.ProtoReflect.Descriptor instead.
+func (*AbandonRaceRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AbandonRaceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AbandonRaceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Response to AbandonRace call.
+type AbandonRaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Race          *Race                  `protobuf:"bytes,1,opt,name=race,proto3" json:"race,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbandonRaceResponse) Reset() {
+	*x = AbandonRaceResponse{}
+	mi := &file_racing_racing_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbandonRaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbandonRaceResponse) ProtoMessage() {}
+
+func (x *AbandonRaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbandonRaceResponse.ProtoReflect.Descriptor instead.
+func (*AbandonRaceResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AbandonRaceResponse) GetRace() *Race {
+	if x != nil {
+		return x.Race
+	}
+	return nil
+}
+
+// Request for ReinstateRace call.
+type ReinstateRaceRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Optional new start time; the existing start time is kept when unset.
+	NewStartTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=new_start_time,json=newStartTime,proto3" json:"new_start_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReinstateRaceRequest) Reset() {
+	*x = ReinstateRaceRequest{}
+	mi := &file_racing_racing_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReinstateRaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateRaceRequest) ProtoMessage() {}
+
+func (x *ReinstateRaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateRaceRequest.ProtoReflect.Descriptor instead.
+func (*ReinstateRaceRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReinstateRaceRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReinstateRaceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReinstateRaceRequest) GetNewStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NewStartTime
+	}
+	return nil
+}
+
+// Response to ReinstateRace call.
+type ReinstateRaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Race          *Race                  `protobuf:"bytes,1,opt,name=race,proto3" json:"race,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReinstateRaceResponse) Reset() {
+	*x = ReinstateRaceResponse{}
+	mi := &file_racing_racing_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReinstateRaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateRaceResponse) ProtoMessage() {}
+
+func (x *ReinstateRaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateRaceResponse.ProtoReflect.Descriptor instead.
+func (*ReinstateRaceResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReinstateRaceResponse) GetRace() *Race {
+	if x != nil {
+		return x.Race
+	}
+	return nil
+}
+
+// Request for Search call.
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Query is free text. Every word must match, and the last word is matched as
+	// a prefix so partially typed words still find results.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Limit caps the number of results. Defaults to 10.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_racing_racing_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Response to Search call.
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_racing_racing_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SearchResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// A race matching a search.
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Race  *Race                  `protobuf:"bytes,1,opt,name=race,proto3" json:"race,omitempty"`
+	// Snippet is the matching text with each hit wrapped in <b></b>.
+	Snippet string `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	// Score ranks the result; higher is a better match.
+	Score         float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_racing_racing_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SearchResult) GetRace() *Race {
+	if x != nil {
+		return x.Race
+	}
+	return nil
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// Request for ImportRaces call. A client streams any number of these.
+type ImportRacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Provider names the feed the rows come from. External IDs only need be
+	// unique within a provider. Set on the first message; later messages may
+	// leave it empty.
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// If true nothing is written, and the response says what would change. Read
+	// from the first message.
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Rows to import, in file order.
+	Rows          []*ImportRaceRow `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRacesRequest) Reset() {
+	*x = ImportRacesRequest{}
+	mi := &file_racing_racing_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRacesRequest) ProtoMessage() {}
+
+func (x *ImportRacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRacesRequest.ProtoReflect.Descriptor instead.
+func (*ImportRacesRequest) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImportRacesRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ImportRacesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportRacesRequest) GetRows() []*ImportRaceRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// One race from a provider's feed, with the meeting it belongs to.
+type ImportRaceRow struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Row is the row's position in the provider's file, counting from 1, used
+	// to report errors. Rows left at 0 are numbered by their place in the stream.
+	Row int64 `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	// MeetingExternalID is the provider's ID for the meeting.
+	MeetingExternalId string `protobuf:"bytes,2,opt,name=meeting_external_id,json=meetingExternalId,proto3" json:"meeting_external_id,omitempty"`
+	// MeetingName is the name of the meeting.
+	MeetingName string `protobuf:"bytes,3,opt,name=meeting_name,json=meetingName,proto3" json:"meeting_name,omitempty"`
+	// ExternalID is the provider's ID for the race.
+	ExternalId string `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// Name is the official name given to the race.
+	Name string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// Number represents the number of the race within its meeting.
+	Number int64 `protobuf:"varint,6,opt,name=number,proto3" json:"number,omitempty"`
+	// Visible represents whether or not the race is visible.
+	Visible bool `protobuf:"varint,7,opt,name=visible,proto3" json:"visible,omitempty"`
+	// AdvertisedStartTime is the time the race is advertised to run.
+	AdvertisedStartTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=advertised_start_time,json=advertisedStartTime,proto3" json:"advertised_start_time,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ImportRaceRow) Reset() {
+	*x = ImportRaceRow{}
+	mi := &file_racing_racing_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRaceRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRaceRow) ProtoMessage() {}
+
+func (x *ImportRaceRow) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRaceRow.ProtoReflect.Descriptor instead.
+func (*ImportRaceRow) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ImportRaceRow) GetRow() int64 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRaceRow) GetMeetingExternalId() string {
+	if x != nil {
+		return x.MeetingExternalId
+	}
+	return ""
+}
+
+func (x *ImportRaceRow) GetMeetingName() string {
+	if x != nil {
+		return x.MeetingName
+	}
+	return ""
+}
+
+func (x *ImportRaceRow) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *ImportRaceRow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportRaceRow) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *ImportRaceRow) GetVisible() bool {
+	if x != nil {
+		return x.Visible
+	}
+	return false
+}
+
+func (x *ImportRaceRow) GetAdvertisedStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AdvertisedStartTime
+	}
+	return nil
+}
+
+// Response to ImportRaces call.
+type ImportRacesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Applied is true when the changes were written: the import was not a dry
+	// run and every row was valid.
+	Applied bool `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"`
+	// Errors lists every problem found with the rows, in row order. Nothing is
+	// written when there are any.
+	Errors []*ImportRowError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Changes lists the meetings and races created or updated, in row order.
+	// Those left as they were are only counted in the summary.
+	Changes []*ImportChange `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	// Summary counts the rows and what was done with them.
+	Summary *ImportSummary `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	// Warnings lists the rows imported only in part, in row order: a start
+	// time left as it was because a trader has delayed or abandoned the race.
+	Warnings      []*ImportRowError `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRacesResponse) Reset() {
+	*x = ImportRacesResponse{}
+	mi := &file_racing_racing_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRacesResponse) ProtoMessage() {}
+
+func (x *ImportRacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRacesResponse.ProtoReflect.Descriptor instead.
+func (*ImportRacesResponse) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ImportRacesResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *ImportRacesResponse) GetErrors() []*ImportRowError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ImportRacesResponse) GetChanges() []*ImportChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ImportRacesResponse) GetSummary() *ImportSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *ImportRacesResponse) GetWarnings() []*ImportRowError {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// A problem with one field of an imported row.
+type ImportRowError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Row   int64                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	// Field is the ImportRaceRow field at fault, e.g. "advertised_start_time".
+	Field         string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Description   string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRowError) Reset() {
+	*x = ImportRowError{}
+	mi := &file_racing_racing_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRowError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRowError) ProtoMessage() {}
+
+func (x *ImportRowError) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRowError.ProtoReflect.Descriptor instead.
+func (*ImportRowError) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportRowError) GetRow() int64 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportRowError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ImportRowError) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// A meeting or race an import created or updated.
+type ImportChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Row is the first row the change came from.
+	Row    int64        `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Action ImportAction `protobuf:"varint,2,opt,name=action,proto3,enum=racing.ImportAction" json:"action,omitempty"`
+	// Kind is "meeting" or "race".
+	Kind string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	// ID is the meeting or race ID. For one created by a dry run it is the ID
+	// it would have been given.
+	Id int64 `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`
+	// ExternalID is the provider's ID for the meeting or race.
+	ExternalId string `protobuf:"bytes,5,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// Fields lists the fields set by a create or changed by an update.
+	Fields        []*FieldChange `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportChange) Reset() {
+	*x = ImportChange{}
+	mi := &file_racing_racing_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportChange) ProtoMessage() {}
+
+func (x *ImportChange) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportChange.ProtoReflect.Descriptor instead.
+func (*ImportChange) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportChange) GetRow() int64 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportChange) GetAction() ImportAction {
+	if x != nil {
+		return x.Action
+	}
+	return ImportAction_IMPORT_ACTION_UNSPECIFIED
+}
+
+func (x *ImportChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ImportChange) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ImportChange) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *ImportChange) GetFields() []*FieldChange {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// A field's value before and after an import. Values are rendered as text:
+// start times in RFC 3339, and empty before a create.
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue      string                 `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      string                 `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_racing_racing_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetOldValue() string {
+	if x != nil {
+		return x.OldValue
+	}
+	return ""
+}
+
+func (x *FieldChange) GetNewValue() string {
+	if x != nil {
+		return x.NewValue
+	}
+	return ""
+}
+
+// Counts of what an import did.
+type ImportSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rows is the number of rows received.
+	Rows              int64 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	MeetingsCreated   int64 `protobuf:"varint,2,opt,name=meetings_created,json=meetingsCreated,proto3" json:"meetings_created,omitempty"`
+	MeetingsUpdated   int64 `protobuf:"varint,3,opt,name=meetings_updated,json=meetingsUpdated,proto3" json:"meetings_updated,omitempty"`
+	MeetingsUnchanged int64 `protobuf:"varint,4,opt,name=meetings_unchanged,json=meetingsUnchanged,proto3" json:"meetings_unchanged,omitempty"`
+	RacesCreated      int64 `protobuf:"varint,5,opt,name=races_created,json=racesCreated,proto3" json:"races_created,omitempty"`
+	RacesUpdated      int64 `protobuf:"varint,6,opt,name=races_updated,json=racesUpdated,proto3" json:"races_updated,omitempty"`
+	RacesUnchanged    int64 `protobuf:"varint,7,opt,name=races_unchanged,json=racesUnchanged,proto3" json:"races_unchanged,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ImportSummary) Reset() {
+	*x = ImportSummary{}
+	mi := &file_racing_racing_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSummary) ProtoMessage() {}
+
+func (x *ImportSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_racing_racing_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSummary.ProtoReflect.Descriptor instead.
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return file_racing_racing_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ImportSummary) GetRows() int64 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetMeetingsCreated() int64 {
+	if x != nil {
+		return x.MeetingsCreated
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetMeetingsUpdated() int64 {
+	if x != nil {
+		return x.MeetingsUpdated
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetMeetingsUnchanged() int64 {
+	if x != nil {
+		return x.MeetingsUnchanged
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetRacesCreated() int64 {
+	if x != nil {
+		return x.RacesCreated
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetRacesUpdated() int64 {
+	if x != nil {
+		return x.RacesUpdated
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetRacesUnchanged() int64 {
+	if x != nil {
+		return x.RacesUnchanged
+	}
+	return 0
+}
+
+var File_racing_racing_proto protoreflect.FileDescriptor
+
+const file_racing_racing_proto_rawDesc = "" +
+	"\n" +
+	"\x13racing/racing.proto\x12\x06racing\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17validate/validate.proto\"\xc8\x02\n" +
+	"\x10ListRacesRequest\x126\n" +
+	"\x06filter\x18\x01 \x01(\v2\x1e.racing.ListRacesRequestFilterR\x06filter\x12 \n" +
+	"\x04sort\x18\x02 \x01(\v2\f.racing.SortR\x04sort\x12*\n" +
+	"\x05sorts\x18\x03 \x03(\v2\f.racing.SortB\x06\xc2\xf3\x18\x02 \x04R\x05sorts\x127\n" +
+	"\tread_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12%\n" +
+	"\x0einclude_facets\x18\x05 \x01(\bR\rincludeFacets\x12&\n" +
+	"\tpage_size\x18\x06 \x01(\x05B\t\xc2\xf3\x18\x05\x10\x00\x18\xe8\aR\bpageSize\x12&\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tB\a\xc2\xf3\x18\x038\xe8\aR\tpageToken\"\x8b\x01\n" +
+	"\x11ListRacesResponse\x12\"\n" +
+	"\x05races\x18\x01 \x03(\v2\f.racing.RaceR\x05races\x12*\n" +
+	"\x06facets\x18\x02 \x01(\v2\x12.racing.RaceFacetsR\x06facets\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xd8\x01\n" +
+	"\n" +
+	"RaceFacets\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x03R\n" +
+	"totalCount\x120\n" +
+	"\bmeetings\x18\x02 \x03(\v2\x14.racing.MeetingCountR\bmeetings\x12/\n" +
+	"\bstatuses\x18\x03 \x03(\v2\x13.racing.StatusCountR\bstatuses\x12#\n" +
+	"\rvisible_count\x18\x04 \x01(\x03R\fvisibleCount\x12!\n" +
+	"\fhidden_count\x18\x05 \x01(\x03R\vhiddenCount\"C\n" +
+	"\fMeetingCount\x12\x1d\n" +
+	"\n" +
+	"meeting_id\x18\x01 \x01(\x03R\tmeetingId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"O\n" +
+	"\vStatusCount\x12*\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x12.racing.RaceStatusR\x06status\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"f\n" +
+	"\x16ListRacesRequestFilter\x12)\n" +
+	"\vmeeting_ids\x18\x01 \x03(\x03B\b\xc2\xf3\x18\x04\x10\x01 dR\n" +
+	"meetingIds\x12!\n" +
+	"\fonly_visible\x18\x02 \x01(\bR\vonlyVisible\"\x82\x01\n" +
+	"\x04Sort\x12K\n" +
+	"\x05field\x18\x01 \x01(\tB5\xc2\xf3\x181*\x15advertised_start_time*\n" +
+	"meeting_id*\x04name*\x06numberR\x05field\x12-\n" +
+	"\tdirection\x18\x02 \x01(\tB\x0f\xc2\xf3\x18\v*\x03asc*\x04descR\tdirection\"\xf7\x01\n" +
+	"\x04Race\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"meeting_id\x18\x02 \x01(\x03R\tmeetingId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06number\x18\x04 \x01(\x03R\x06number\x12\x18\n" +
+	"\avisible\x18\x05 \x01(\bR\avisible\x12N\n" +
+	"\x15advertised_start_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x13advertisedStartTime\x12*\n" +
+	"\x06status\x18\a \x01(\x0e2\x12.racing.RaceStatusR\x06status\"\xca\x02\n" +
+	"\x0eScheduleChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\arace_id\x18\x02 \x01(\x03R\x06raceId\x12.\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1a.racing.ScheduleChangeTypeR\x04type\x12J\n" +
+	"\x13previous_start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x11previousStartTime\x12@\n" +
+	"\x0enew_start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fnewStartTime\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"changed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x8a\x01\n" +
+	"\x0eGetRaceRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\x03B\x06\xc2\xf3\x18\x02\x10\x01R\x02id\x12'\n" +
+	"\x0finclude_history\x18\x02 \x01(\bR\x0eincludeHistory\x127\n" +
+	"\tread_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"\xb1\x01\n" +
+	"\x0fGetRaceResponse\x12 \n" +
+	"\x04race\x18\x01 \x01(\v2\f.racing.RaceR\x04race\x120\n" +
+	"\ahistory\x18\x02 \x03(\v2\x16.racing.ScheduleChangeR\ahistory\x12J\n" +
+	"\x13original_start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x11originalStartTime\"m\n" +
+	"\x14BatchGetRacesRequest\x12\x1c\n" +
+	"\x03ids\x18\x01 \x03(\x03B\n" +
+	"\xc2\xf3\x18\x06\b\x01\x10\x01 dR\x03ids\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"X\n" +
+	"\x15BatchGetRacesResponse\x12\"\n" +
+	"\x05races\x18\x01 \x03(\v2\f.racing.RaceR\x05races\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x03(\x03R\bnotFound\"\x97\x01\n" +
+	"\x10DelayRaceRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\x03B\x06\xc2\xf3\x18\x02\x10\x01R\x02id\x12H\n" +
+	"\x0enew_start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\x06\xc2\xf3\x18\x02\b\x01R\fnewStartTime\x12!\n" +
+	"\x06reason\x18\x03 \x01(\tB\t\xc2\xf3\x18\x05\b\x018\xf4\x03R\x06reason\"5\n" +
+	"\x11DelayRaceResponse\x12 \n" +
+	"\x04race\x18\x01 \x01(\v2\f.racing.RaceR\x04race\"O\n" +
+	"\x12AbandonRaceRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\x03B\x06\xc2\xf3\x18\x02\x10\x01R\x02id\x12!\n" +
+	"\x06reason\x18\x02 \x01(\tB\t\xc2\xf3\x18\x05\b\x018\xf4\x03R\x06reason\"7\n" +
+	"\x13AbandonRaceResponse\x12 \n" +
+	"\x04race\x18\x01 \x01(\v2\f.racing.RaceR\x04race\"\x93\x01\n" +
+	"\x14ReinstateRaceRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\x03B\x06\xc2\xf3\x18\x02\x10\x01R\x02id\x12!\n" +
+	"\x06reason\x18\x02 \x01(\tB\t\xc2\xf3\x18\x05\b\x018\xf4\x03R\x06reason\x12@\n" +
+	"\x0enew_start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fnewStartTime\"9\n" +
+	"\x15ReinstateRaceResponse\x12 \n" +
+	"\x04race\x18\x01 \x01(\v2\f.racing.RaceR\x04race\"O\n" +
+	"\rSearchRequest\x12\x1e\n" +
+	"\x05query\x18\x01 \x01(\tB\b\xc2\xf3\x18\x04\b\x018dR\x05query\x12\x1e\n" +
+	"\x05limit\x18\x02 \x01(\x05B\b\xc2\xf3\x18\x04\x10\x00\x182R\x05limit\"@\n" +
+	"\x0eSearchResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.racing.SearchResultR\aresults\"`\n" +
+	"\fSearchResult\x12 \n" +
+	"\x04race\x18\x01 \x01(\v2\f.racing.RaceR\x04race\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\"\x85\x01\n" +
+	"\x12ImportRacesRequest\x12\"\n" +
+	"\bprovider\x18\x01 \x01(\tB\x06\xc2\xf3\x18\x0282R\bprovider\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x122\n" +
+	"\x04rows\x18\x03 \x03(\v2\x15.racing.ImportRaceRowB\a\xc2\xf3\x18\x03 \xe8\aR\x04rows\"\xab\x02\n" +
+	"\rImportRaceRow\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12.\n" +
+	"\x13meeting_external_id\x18\x02 \x01(\tR\x11meetingExternalId\x12!\n" +
+	"\fmeeting_name\x18\x03 \x01(\tR\vmeetingName\x12\x1f\n" +
+	"\vexternal_id\x18\x04 \x01(\tR\n" +
+	"externalId\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x16\n" +
+	"\x06number\x18\x06 \x01(\x03R\x06number\x12\x18\n" +
+	"\avisible\x18\a \x01(\bR\avisible\x12N\n" +
+	"\x15advertised_start_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x13advertisedStartTime\"\xf4\x01\n" +
+	"\x13ImportRacesResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x01(\bR\aapplied\x12.\n" +
+	"\x06errors\x18\x02 \x03(\v2\x16.racing.ImportRowErrorR\x06errors\x12.\n" +
+	"\achanges\x18\x03 \x03(\v2\x14.racing.ImportChangeR\achanges\x12/\n" +
+	"\asummary\x18\x04 \x01(\v2\x15.racing.ImportSummaryR\asummary\x122\n" +
+	"\bwarnings\x18\x05 \x03(\v2\x16.racing.ImportRowErrorR\bwarnings\"Z\n" +
+	"\x0eImportRowError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\xc0\x01\n" +
+	"\fImportChange\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12,\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x14.racing.ImportActionR\x06action\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x05 \x01(\tR\n" +
+	"externalId\x12+\n" +
+	"\x06fields\x18\x06 \x03(\v2\x13.racing.FieldChangeR\x06fields\"]\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x1b\n" +
+	"\told_value\x18\x02 \x01(\tR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x03 \x01(\tR\bnewValue\"\x9b\x02\n" +
+	"\rImportSummary\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\x03R\x04rows\x12)\n" +
+	"\x10meetings_created\x18\x02 \x01(\x03R\x0fmeetingsCreated\x12)\n" +
+	"\x10meetings_updated\x18\x03 \x01(\x03R\x0fmeetingsUpdated\x12-\n" +
+	"\x12meetings_unchanged\x18\x04 \x01(\x03R\x11meetingsUnchanged\x12#\n" +
+	"\rraces_created\x18\x05 \x01(\x03R\fracesCreated\x12#\n" +
+	"\rraces_updated\x18\x06 \x01(\x03R\fracesUpdated\x12'\n" +
+	"\x0fraces_unchanged\x18\a \x01(\x03R\x0eracesUnchanged*O\n" +
+	"\n" +
+	"RaceStatus\x12\x0f\n" +
+	"\vUNSPECIFIED\x10\x00\x12\b\n" +
+	"\x04OPEN\x10\x01\x12\n" +
+	"\n" +
+	"\x06CLOSED\x10\x02\x12\r\n" +
+	"\tABANDONED\x10\x03\x12\v\n" +
+	"\aDELAYED\x10\x04*a\n" +
+	"\fImportAction\x12\x1d\n" +
+	"\x19IMPORT_ACTION_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14IMPORT_ACTION_CREATE\x10\x01\x12\x18\n" +
+	"\x14IMPORT_ACTION_UPDATE\x10\x02*\xa8\x01\n" +
+	"\x12ScheduleChangeType\x12\x1f\n" +
+	"\x1bSCHEDULE_CHANGE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SCHEDULE_CHANGE_DELAY\x10\x01\x12\x1b\n" +
+	"\x17SCHEDULE_CHANGE_ABANDON\x10\x02\x12\x1d\n" +
+	"\x19SCHEDULE_CHANGE_REINSTATE\x10\x03\x12\x1a\n" +
+	"\x16SCHEDULE_CHANGE_IMPORT\x10\x042\xaf\x04\n" +
+	"\x06Racing\x12@\n" +
+	"\tListRaces\x12\x18.racing.ListRacesRequest\x1a\x19.racing.ListRacesResponse\x12:\n" +
+	"\aGetRace\x12\x16.racing.GetRaceRequest\x1a\x17.racing.GetRaceResponse\x12L\n" +
+	"\rBatchGetRaces\x12\x1c.racing.BatchGetRacesRequest\x1a\x1d.racing.BatchGetRacesResponse\x12@\n" +
+	"\tDelayRace\x12\x18.racing.DelayRaceRequest\x1a\x19.racing.DelayRaceResponse\x12F\n" +
+	"\vAbandonRace\x12\x1a.racing.AbandonRaceRequest\x1a\x1b.racing.AbandonRaceResponse\x12L\n" +
+	"\rReinstateRace\x12\x1c.racing.ReinstateRaceRequest\x1a\x1d.racing.ReinstateRaceResponse\x127\n" +
+	"\x06Search\x12\x15.racing.SearchRequest\x1a\x16.racing.SearchResponse\x12H\n" +
+	"\vImportRaces\x12\x1a.racing.ImportRacesRequest\x1a\x1b.racing.ImportRacesResponse(\x01B6Z4github.com/SylvanSol/Entain_Test/common/proto/racingb\x06proto3"
+
+var (
+	file_racing_racing_proto_rawDescOnce sync.Once
+	file_racing_racing_proto_rawDescData []byte
+)
+
+func file_racing_racing_proto_rawDescGZIP() []byte {
+	file_racing_racing_proto_rawDescOnce.Do(func() {
+		file_racing_racing_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_racing_racing_proto_rawDesc), len(file_racing_racing_proto_rawDesc)))
+	})
+	return file_racing_racing_proto_rawDescData
+}
+
+var file_racing_racing_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_racing_racing_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_racing_racing_proto_goTypes = []any{
+	(RaceStatus)(0),                // 0: racing.RaceStatus
+	(ImportAction)(0),              // 1: racing.ImportAction
+	(ScheduleChangeType)(0),        // 2: racing.ScheduleChangeType
+	(*ListRacesRequest)(nil),       // 3: racing.ListRacesRequest
+	(*ListRacesResponse)(nil),      // 4: racing.ListRacesResponse
+	(*RaceFacets)(nil),             // 5: racing.RaceFacets
+	(*MeetingCount)(nil),           // 6: racing.MeetingCount
+	(*StatusCount)(nil),            // 7: racing.StatusCount
+	(*ListRacesRequestFilter)(nil), // 8: racing.ListRacesRequestFilter
+	(*Sort)(nil),                   // 9: racing.Sort
+	(*Race)(nil),                   // 10: racing.Race
+	(*ScheduleChange)(nil),         // 11: racing.ScheduleChange
+	(*GetRaceRequest)(nil),         // 12: racing.GetRaceRequest
+	(*GetRaceResponse)(nil),        // 13: racing.GetRaceResponse
+	(*BatchGetRacesRequest)(nil),   // 14: racing.BatchGetRacesRequest
+	(*BatchGetRacesResponse)(nil),  // 15: racing.BatchGetRacesResponse
+	(*DelayRaceRequest)(nil),       // 16: racing.DelayRaceRequest
+	(*DelayRaceResponse)(nil),      // 17: racing.DelayRaceResponse
+	(*AbandonRaceRequest)(nil),     // 18: racing.AbandonRaceRequest
+	(*AbandonRaceResponse)(nil),    // 19: racing.AbandonRaceResponse
+	(*ReinstateRaceRequest)(nil),   // 20: racing.ReinstateRaceRequest
+	(*ReinstateRaceResponse)(nil),  // 21: racing.ReinstateRaceResponse
+	(*SearchRequest)(nil),          // 22: racing.SearchRequest
+	(*SearchResponse)(nil),         // 23: racing.SearchResponse
+	(*SearchResult)(nil),           // 24: racing.SearchResult
+	(*ImportRacesRequest)(nil),     // 25: racing.ImportRacesRequest
+	(*ImportRaceRow)(nil),          // 26: racing.ImportRaceRow
+	(*ImportRacesResponse)(nil),    // 27: racing.ImportRacesResponse
+	(*ImportRowError)(nil),         // 28: racing.ImportRowError
+	(*ImportChange)(nil),           // 29: racing.ImportChange
+	(*FieldChange)(nil),            // 30: racing.FieldChange
+	(*ImportSummary)(nil),          // 31: racing.ImportSummary
+	(*fieldmaskpb.FieldMask)(nil),  // 32: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),  // 33: google.protobuf.Timestamp
+}
+var file_racing_racing_proto_depIdxs = []int32{
+	8,  // 0: racing.ListRacesRequest.filter:type_name -> racing.ListRacesRequestFilter
+	9,  // 1: racing.ListRacesRequest.sort:type_name -> racing.Sort
+	9,  // 2: racing.ListRacesRequest.sorts:type_name -> racing.Sort
+	32, // 3: racing.ListRacesRequest.read_mask:type_name -> google.protobuf.FieldMask
+	10, // 4: racing.ListRacesResponse.races:type_name -> racing.Race
+	5,  // 5: racing.ListRacesResponse.facets:type_name -> racing.RaceFacets
+	6,  // 6: racing.RaceFacets.meetings:type_name -> racing.MeetingCount
+	7,  // 7: racing.RaceFacets.statuses:type_name -> racing.StatusCount
+	0,  // 8: racing.StatusCount.status:type_name -> racing.RaceStatus
+	33, // 9: racing.Race.advertised_start_time:type_name -> google.protobuf.Timestamp
+	0,  // 10: racing.Race.status:type_name -> racing.RaceStatus
+	2,  // 11: racing.ScheduleChange.type:type_name -> racing.ScheduleChangeType
+	33, // 12: racing.ScheduleChange.previous_start_time:type_name -> google.protobuf.Timestamp
+	33, // 13: racing.ScheduleChange.new_start_time:type_name -> google.protobuf.Timestamp
+	33, // 14: racing.ScheduleChange.changed_at:type_name -> google.protobuf.Timestamp
+	32, // 15: racing.GetRaceRequest.read_mask:type_name -> google.protobuf.FieldMask
+	10, // 16: racing.GetRaceResponse.race:type_name -> racing.Race
+	11, // 17: racing.GetRaceResponse.history:type_name -> racing.ScheduleChange
+	33, // 18: racing.GetRaceResponse.original_start_time:type_name -> google.protobuf.Timestamp
+	32, // 19: racing.BatchGetRacesRequest.read_mask:type_name -> google.protobuf.FieldMask
+	10, // 20: racing.BatchGetRacesResponse.races:type_name -> racing.Race
+	33, // 21: racing.DelayRaceRequest.new_start_time:type_name -> google.protobuf.Timestamp
+	10, // 22: racing.DelayRaceResponse.race:type_name -> racing.Race
+	10, // 23: racing.AbandonRaceResponse.race:type_name -> racing.Race
+	33, // 24: racing.ReinstateRaceRequest.new_start_time:type_name -> google.protobuf.Timestamp
+	10, // 25: racing.ReinstateRaceResponse.race:type_name -> racing.Race
+	24, // 26: racing.SearchResponse.results:type_name -> racing.SearchResult
+	10, // 27: racing.SearchResult.race:type_name -> racing.Race
+	26, // 28: racing.ImportRacesRequest.rows:type_name -> racing.ImportRaceRow
+	33, // 29: racing.ImportRaceRow.advertised_start_time:type_name -> google.protobuf.Timestamp
+	28, // 30: racing.ImportRacesResponse.errors:type_name -> racing.ImportRowError
+	29, // 31: racing.ImportRacesResponse.changes:type_name -> racing.ImportChange
+	31, // 32: racing.ImportRacesResponse.summary:type_name -> racing.ImportSummary
+	28, // 33: racing.ImportRacesResponse.warnings:type_name -> racing.ImportRowError
+	1,  // 34: racing.ImportChange.action:type_name -> racing.ImportAction
+	30, // 35: racing.ImportChange.fields:type_name -> racing.FieldChange
+	3,  // 36: racing.Racing.ListRaces:input_type -> racing.ListRacesRequest
+	12, // 37: racing.Racing.GetRace:input_type -> racing.GetRaceRequest
+	14, // 38: racing.Racing.BatchGetRaces:input_type -> racing.BatchGetRacesRequest
+	16, // 39: racing.Racing.DelayRace:input_type -> racing.DelayRaceRequest
+	18, // 40: racing.Racing.AbandonRace:input_type -> racing.AbandonRaceRequest
+	20, // 41: racing.Racing.ReinstateRace:input_type -> racing.ReinstateRaceRequest
+	22, // 42: racing.Racing.Search:input_type -> racing.SearchRequest
+	25, // 43: racing.Racing.ImportRaces:input_type -> racing.ImportRacesRequest
+	4,  // 44: racing.Racing.ListRaces:output_type -> racing.ListRacesResponse
+	13, // 45: racing.Racing.GetRace:output_type -> racing.GetRaceResponse
+	15, // 46: racing.Racing.BatchGetRaces:output_type -> racing.BatchGetRacesResponse
+	17, // 47: racing.Racing.DelayRace:output_type -> racing.DelayRaceResponse
+	19, // 48: racing.Racing.AbandonRace:output_type -> racing.AbandonRaceResponse
+	21, // 49: racing.Racing.ReinstateRace:output_type -> racing.ReinstateRaceResponse
+	23, // 50: racing.Racing.Search:output_type -> racing.SearchResponse
+	27, // 51: racing.Racing.ImportRaces:output_type -> racing.ImportRacesResponse
+	44, // [44:52] is the sub-list for method output_type
+	36, // [36:44] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
+}
+
+func init() { file_racing_racing_proto_init() }
+func file_racing_racing_proto_init() {
+	if File_racing_racing_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_racing_racing_proto_rawDesc), len(file_racing_racing_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_racing_racing_proto_goTypes,
+		DependencyIndexes: file_racing_racing_proto_depIdxs,
+		EnumInfos:         file_racing_racing_proto_enumTypes,
+		MessageInfos:      file_racing_racing_proto_msgTypes,
+	}.Build()
+	File_racing_racing_proto = out.File
+	file_racing_racing_proto_goTypes = nil
+	file_racing_racing_proto_depIdxs = nil
+}
@@ -0,0 +1,409 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.30.2
+// source: racing/racing.proto
+
+package racing
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Racing_ListRaces_FullMethodName     = "/racing.Racing/ListRaces"
+	Racing_GetRace_FullMethodName       = "/racing.Racing/GetRace"
+	Racing_BatchGetRaces_FullMethodName = "/racing.Racing/BatchGetRaces"
+	Racing_DelayRace_FullMethodName     = "/racing.Racing/DelayRace"
+	Racing_AbandonRace_FullMethodName   = "/racing.Racing/AbandonRace"
+	Racing_ReinstateRace_FullMethodName = "/racing.Racing/ReinstateRace"
+	Racing_Search_FullMethodName        = "/racing.Racing/Search"
+	Racing_ImportRaces_FullMethodName   = "/racing.Racing/ImportRaces"
+)
+
+// RacingClient is the client API for Racing service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Racing is served over gRPC by the racing service. The api gateway's HTTP
+// routes for it are declared in api/proto/gateway.yaml, not here.
+type RacingClient interface {
+	// ListRaces returns a list of all races.
+	ListRaces(ctx context.Context, in *ListRacesRequest, opts ...grpc.CallOption) (*ListRacesResponse, error)
+	// GetRace returns a single race by ID
+	GetRace(ctx context.Context, in *GetRaceRequest, opts ...grpc.CallOption) (*GetRaceResponse, error)
+	// BatchGetRaces returns several races by ID in one call.
+	BatchGetRaces(ctx context.Context, in *BatchGetRacesRequest, opts ...grpc.CallOption) (*BatchGetRacesResponse, error)
+	// DelayRace moves a race to a new start time and records why.
+	DelayRace(ctx context.Context, in *DelayRaceRequest, opts ...grpc.CallOption) (*DelayRaceResponse, error)
+	// AbandonRace marks a race as abandoned and records why.
+	AbandonRace(ctx context.Context, in *AbandonRaceRequest, opts ...grpc.CallOption) (*AbandonRaceResponse, error)
+	// ReinstateRace brings an abandoned race back, optionally at a new start time.
+	ReinstateRace(ctx context.Context, in *ReinstateRaceRequest, opts ...grpc.CallOption) (*ReinstateRaceResponse, error)
+	// Search finds visible races whose name matches free text, best match first.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// ImportRaces creates and updates meetings and races from a provider's feed,
+	// matching them on the provider's own IDs. Rows are streamed in and applied
+	// in one transaction when the stream ends. Nothing is written if any row is
+	// invalid or the import is a dry run.
+	ImportRaces(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportRacesRequest, ImportRacesResponse], error)
+}
+
+type racingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRacingClient(cc grpc.ClientConnInterface) RacingClient {
+	return &racingClient{cc}
+}
+
+func (c *racingClient) ListRaces(ctx context.Context, in *ListRacesRequest, opts ...grpc.CallOption) (*ListRacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRacesResponse)
+	err := c.cc.Invoke(ctx, Racing_ListRaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) GetRace(ctx context.Context, in *GetRaceRequest, opts ...grpc.CallOption) (*GetRaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRaceResponse)
+	err := c.cc.Invoke(ctx, Racing_GetRace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) BatchGetRaces(ctx context.Context, in *BatchGetRacesRequest, opts ...grpc.CallOption) (*BatchGetRacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetRacesResponse)
+	err := c.cc.Invoke(ctx, Racing_BatchGetRaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) DelayRace(ctx context.Context, in *DelayRaceRequest, opts ...grpc.CallOption) (*DelayRaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DelayRaceResponse)
+	err := c.cc.Invoke(ctx, Racing_DelayRace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) AbandonRace(ctx context.Context, in *AbandonRaceRequest, opts ...grpc.CallOption) (*AbandonRaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AbandonRaceResponse)
+	err := c.cc.Invoke(ctx, Racing_AbandonRace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) ReinstateRace(ctx context.Context, in *ReinstateRaceRequest, opts ...grpc.CallOption) (*ReinstateRaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReinstateRaceResponse)
+	err := c.cc.Invoke(ctx, Racing_ReinstateRace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, Racing_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *racingClient) ImportRaces(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportRacesRequest, ImportRacesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Racing_ServiceDesc.Streams[0], Racing_ImportRaces_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportRacesRequest, ImportRacesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Racing_ImportRacesClient = grpc.ClientStreamingClient[ImportRacesRequest, ImportRacesResponse]
+
+// RacingServer is the server API for Racing service.
+// All implementations must embed UnimplementedRacingServer
+// for forward compatibility.
+//
+// Racing is served over gRPC by the racing service. The api gateway's HTTP
+// routes for it are declared in api/proto/gateway.yaml, not here.
+type RacingServer interface {
+	// ListRaces returns a list of all races.
+	ListRaces(context.Context, *ListRacesRequest) (*ListRacesResponse, error)
+	// GetRace returns a single race by ID
+	GetRace(context.Context, *GetRaceRequest) (*GetRaceResponse, error)
+	// BatchGetRaces returns several races by ID in one call.
+	BatchGetRaces(context.Context, *BatchGetRacesRequest) (*BatchGetRacesResponse, error)
+	// DelayRace moves a race to a new start time and records why.
+	DelayRace(context.Context, *DelayRaceRequest) (*DelayRaceResponse, error)
+	// AbandonRace marks a race as abandoned and records why.
+	AbandonRace(context.Context, *AbandonRaceRequest) (*AbandonRaceResponse, error)
+	// ReinstateRace brings an abandoned race back, optionally at a new start time.
+	ReinstateRace(context.Context, *ReinstateRaceRequest) (*ReinstateRaceResponse, error)
+	// Search finds visible races whose name matches free text, best match first.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// ImportRaces creates and updates meetings and races from a provider's feed,
+	// matching them on the provider's own IDs. Rows are streamed in and applied
+	// in one transaction when the stream ends. Nothing is written if any row is
+	// invalid or the import is a dry run.
+	ImportRaces(grpc.ClientStreamingServer[ImportRacesRequest, ImportRacesResponse]) error
+	mustEmbedUnimplementedRacingServer()
+}
+
+// UnimplementedRacingServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRacingServer struct{}
+
+func (UnimplementedRacingServer) ListRaces(context.Context, *ListRacesRequest) (*ListRacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRaces not implemented")
+}
+func (UnimplementedRacingServer) GetRace(context.Context, *GetRaceRequest) (*GetRaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRace not implemented")
+}
+func (UnimplementedRacingServer) BatchGetRaces(context.Context, *BatchGetRacesRequest) (*BatchGetRacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetRaces not implemented")
+}
+func (UnimplementedRacingServer) DelayRace(context.Context, *DelayRaceRequest) (*DelayRaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelayRace not implemented")
+}
+func (UnimplementedRacingServer) AbandonRace(context.Context, *AbandonRaceRequest) (*AbandonRaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbandonRace not implemented")
+}
+func (UnimplementedRacingServer) ReinstateRace(context.Context, *ReinstateRaceRequest) (*ReinstateRaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReinstateRace not implemented")
+}
+func (UnimplementedRacingServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedRacingServer) ImportRaces(grpc.ClientStreamingServer[ImportRacesRequest, ImportRacesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportRaces not implemented")
+}
+func (UnimplementedRacingServer) mustEmbedUnimplementedRacingServer() {}
+func (UnimplementedRacingServer) testEmbeddedByValue()                {}
+
+// UnsafeRacingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RacingServer will
+// result in compilation errors.
+type UnsafeRacingServer interface {
+	mustEmbedUnimplementedRacingServer()
+}
+
+func RegisterRacingServer(s grpc.ServiceRegistrar, srv RacingServer) {
+	// If the following call pancis, it indicates UnimplementedRacingServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Racing_ServiceDesc, srv)
+}
+
+func _Racing_ListRaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).ListRaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_ListRaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).ListRaces(ctx, req.(*ListRacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_GetRace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).GetRace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_GetRace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).GetRace(ctx, req.(*GetRaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_BatchGetRaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).BatchGetRaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_BatchGetRaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).BatchGetRaces(ctx, req.(*BatchGetRacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_DelayRace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelayRaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).DelayRace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_DelayRace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).DelayRace(ctx, req.(*DelayRaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_AbandonRace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbandonRaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).AbandonRace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_AbandonRace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).AbandonRace(ctx, req.(*AbandonRaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_ReinstateRace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReinstateRaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).ReinstateRace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_ReinstateRace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).ReinstateRace(ctx, req.(*ReinstateRaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RacingServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Racing_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RacingServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Racing_ImportRaces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RacingServer).ImportRaces(&grpc.GenericServerStream[ImportRacesRequest, ImportRacesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Racing_ImportRacesServer = grpc.ClientStreamingServer[ImportRacesRequest, ImportRacesResponse]
+
+// Racing_ServiceDesc is the grpc.ServiceDesc for Racing service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Racing_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "racing.Racing",
+	HandlerType: (*RacingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRaces",
+			Handler:    _Racing_ListRaces_Handler,
+		},
+		{
+			MethodName: "GetRace",
+			Handler:    _Racing_GetRace_Handler,
+		},
+		{
+			MethodName: "BatchGetRaces",
+			Handler:    _Racing_BatchGetRaces_Handler,
+		},
+		{
+			MethodName: "DelayRace",
+			Handler:    _Racing_DelayRace_Handler,
+		},
+		{
+			MethodName: "AbandonRace",
+			Handler:    _Racing_AbandonRace_Handler,
+		},
+		{
+			MethodName: "ReinstateRace",
+			Handler:    _Racing_ReinstateRace_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Racing_Search_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportRaces",
+			Handler:       _Racing_ImportRaces_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "racing/racing.proto",
+}
@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: validate/validate.proto
+
+package validate
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FieldRules declares the constraints a request field must satisfy. They are
+// enforced by the validate package's gRPC interceptor before a handler runs.
+type FieldRules struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required rejects the zero value: 0, false, an unset message or an empty
+	// list, and a string that is empty or only whitespace.
+	Required bool `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	// Min is the smallest allowed value of an integer field, inclusive. For a
+	// repeated field it applies to every element.
+	Min *int64 `protobuf:"varint,2,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	// Max is the largest allowed value of an integer field, inclusive. For a
+	// repeated field it applies to every element.
+	Max *int64 `protobuf:"varint,3,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	// MaxItems is the largest number of elements allowed in a repeated field.
+	MaxItems *uint32 `protobuf:"varint,4,opt,name=max_items,json=maxItems,proto3,oneof" json:"max_items,omitempty"`
+	// In lists the allowed values of a string field, compared case-insensitively.
+	// An empty string is always allowed unless the field is also required.
+	In []string `protobuf:"bytes,5,rep,name=in,proto3" json:"in,omitempty"`
+	// DefinedOnly rejects enum values that are not declared in the enum.
+	DefinedOnly bool `protobuf:"varint,6,opt,name=defined_only,json=definedOnly,proto3" json:"defined_only,omitempty"`
+	// MaxLen is the largest number of characters allowed in a string field.
+	MaxLen        *uint32 `protobuf:"varint,7,opt,name=max_len,json=maxLen,proto3,oneof" json:"max_len,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	mi := &file_validate_validate_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_validate_validate_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_validate_validate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *FieldRules) GetMin() int64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMax() int64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxItems() uint32 {
+	if x != nil && x.MaxItems != nil {
+		return *x.MaxItems
+	}
+	return 0
+}
+
+func (x *FieldRules) GetIn() []string {
+	if x != nil {
+		return x.In
+	}
+	return nil
+}
+
+func (x *FieldRules) GetDefinedOnly() bool {
+	if x != nil {
+		return x.DefinedOnly
+	}
+	return false
+}
+
+func (x *FieldRules) GetMaxLen() uint32 {
+	if x != nil && x.MaxLen != nil {
+		return *x.MaxLen
+	}
+	return 0
+}
+
+var file_validate_validate_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         51000,
+		Name:          "validate.rules",
+		Tag:           "bytes,51000,opt,name=rules",
+		Filename:      "validate/validate.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional validate.FieldRules rules = 51000;
+	E_Rules = &file_validate_validate_proto_extTypes[0]
+)
+
+var File_validate_validate_proto protoreflect.FileDescriptor
+
+const file_validate_validate_proto_rawDesc = "" +
+	"\n" +
+	"\x17validate/validate.proto\x12\bvalidate\x1a google/protobuf/descriptor.proto\"\xf3\x01\n" +
+	"\n" +
+	"FieldRules\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12\x15\n" +
+	"\x03min\x18\x02 \x01(\x03H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x03 \x01(\x03H\x01R\x03max\x88\x01\x01\x12 \n" +
+	"\tmax_items\x18\x04 \x01(\rH\x02R\bmaxItems\x88\x01\x01\x12\x0e\n" +
+	"\x02in\x18\x05 \x03(\tR\x02in\x12!\n" +
+	"\fdefined_only\x18\x06 \x01(\bR\vdefinedOnly\x12\x1c\n" +
+	"\amax_len\x18\a \x01(\rH\x03R\x06maxLen\x88\x01\x01B\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\f\n" +
+	"\n" +
+	"_max_itemsB\n" +
+	"\n" +
+	"\b_max_len:K\n" +
+	"\x05rules\x12\x1d.google.protobuf.FieldOptions\x18\xb8\x8e\x03 \x01(\v2\x14.validate.FieldRulesR\x05rulesB8Z6github.com/SylvanSol/Entain_Test/common/proto/validateb\x06proto3"
+
+var (
+	file_validate_validate_proto_rawDescOnce sync.Once
+	file_validate_validate_proto_rawDescData []byte
+)
+
+func file_validate_validate_proto_rawDescGZIP() []byte {
+	file_validate_validate_proto_rawDescOnce.Do(func() {
+		file_validate_validate_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_validate_validate_proto_rawDesc), len(file_validate_validate_proto_rawDesc)))
+	})
+	return file_validate_validate_proto_rawDescData
+}
+
+var file_validate_validate_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_validate_validate_proto_goTypes = []any{
+	(*FieldRules)(nil),                // 0: validate.FieldRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_validate_validate_proto_depIdxs = []int32{
+	1, // 0: validate.rules:extendee -> google.protobuf.FieldOptions
+	0, // 1: validate.rules:type_name -> validate.FieldRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_validate_validate_proto_init() }
+func file_validate_validate_proto_init() {
+	if File_validate_validate_proto != nil {
+		return
+	}
+	file_validate_validate_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_validate_validate_proto_rawDesc), len(file_validate_validate_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_validate_validate_proto_goTypes,
+		DependencyIndexes: file_validate_validate_proto_depIdxs,
+		MessageInfos:      file_validate_validate_proto_msgTypes,
+		ExtensionInfos:    file_validate_validate_proto_extTypes,
+	}.Build()
+	File_validate_validate_proto = out.File
+	file_validate_validate_proto_goTypes = nil
+	file_validate_validate_proto_depIdxs = nil
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// APIKeyHeader is the HTTP header, and in lower case the gRPC metadata key,
+// that names a client by its API key.
+const APIKeyHeader = "X-API-Key"
+
+// KeySet is the API keys that are given buckets of their own. Any other key is
+// ignored, so a client cannot escape its limit by sending a new key with each
+// request.
+type KeySet map[string]struct{}
+
+// ParseKeys parses a comma-separated list of API keys.
+func ParseKeys(list string) KeySet {
+	keys := KeySet{}
+	for _, key := range strings.Split(list, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys[key] = struct{}{}
+		}
+	}
+	return keys
+}
+
+// client names a client by apiKey when it is in the set, and otherwise by
+// addr.
+func (k KeySet) client(apiKey, addr string) string {
+	if _, ok := k[apiKey]; ok && apiKey != "" {
+		return "key:" + apiKey
+	}
+	return "ip:" + addr
+}
+
+// UnaryServerInterceptor limits every RPC except the exempt methods, keyed by
+// full method name and client. It refuses a request over the limit with a
+// ResourceExhausted error saying when to retry.
+//
+// A client is named by its API key when that is in keys, or failing that its
+// address. For peers in trusted, normally the api gateway, the address is the
+// last one in the x-forwarded-for metadata, so the gateway's clients are told
+// apart rather than sharing one bucket.
+func UnaryServerInterceptor(l *Limiter, keys KeySet, trusted []netip.Prefix, exempt ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(exempt, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		if d := l.Allow(info.FullMethod, grpcClient(ctx, keys, trusted)); !d.Allowed {
+			return nil, apierr.ResourceExhausted(info.FullMethod, "rate limit exceeded", d.RetryAfter)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor limits streaming RPCs as UnaryServerInterceptor
+// limits unary ones. Opening a stream takes one request from the client's
+// bucket, however many messages it then carries.
+func StreamServerInterceptor(l *Limiter, keys KeySet, trusted []netip.Prefix, exempt ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(exempt, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		if d := l.Allow(info.FullMethod, grpcClient(ss.Context(), keys, trusted)); !d.Allowed {
+			return apierr.ResourceExhausted(info.FullMethod, "rate limit exceeded", d.RetryAfter)
+		}
+		return handler(srv, ss)
+	}
+}
+
+func grpcClient(ctx context.Context, keys KeySet, trusted []netip.Prefix) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var apiKey string
+	if values := md.Get(strings.ToLower(APIKeyHeader)); len(values) > 0 {
+		apiKey = values[0]
+	}
+
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = hostOf(p.Addr.String())
+	}
+	if ip, err := netip.ParseAddr(addr); err == nil && isTrusted(ip, trusted) {
+		if fwd := md.Get("x-forwarded-for"); len(fwd) > 0 {
+			hops := strings.Split(fwd[len(fwd)-1], ",")
+			addr = strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	return keys.client(apiKey, addr)
+}
+
+// ClientOf names the client behind an HTTP request by its API key when that is
+// in keys, or failing that its address. Forwarding headers are ignored, since
+// any client can set them.
+func ClientOf(keys KeySet, apiKey, remoteAddr string) string {
+	return keys.client(apiKey, hostOf(remoteAddr))
+}
+
+// ParsePrefixes parses a comma-separated list of CIDR prefixes.
+func ParsePrefixes(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
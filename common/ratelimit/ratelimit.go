@@ -0,0 +1,147 @@
+// Package ratelimit throttles clients with a token bucket per client and route.
+//
+// Each bucket holds up to Burst tokens and refills at Rate tokens a second. A
+// request takes one token, and is refused when the bucket is empty.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is the token bucket for one route.
+type Limit struct {
+	// Rate is the number of requests per second a client may make on average.
+	// Zero means unlimited.
+	Rate float64 `yaml:"rate"`
+	// Burst is the number of requests a client may make at once.
+	Burst int `yaml:"burst"`
+}
+
+// Unlimited reports whether l imposes no limit.
+func (l Limit) Unlimited() bool {
+	return l.Rate == 0
+}
+
+// Decision is the outcome of a request against its bucket, in the terms of the
+// RateLimit response headers.
+type Decision struct {
+	Allowed bool
+	// Limit is the bucket size; zero when the route is unlimited.
+	Limit int
+	// Remaining is the number of requests the client may still make at once.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request would be allowed. It is
+	// only set when the request was refused.
+	RetryAfter time.Duration
+}
+
+type bucketKey struct {
+	route  string
+	client string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter holds the buckets of every client.
+type Limiter struct {
+	def    Limit
+	routes map[string]Limit
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+// New returns a Limiter applying def to every route except those in routes,
+// which are keyed by HTTP path or full gRPC method name.
+func New(def Limit, routes map[string]Limit) *Limiter {
+	return &Limiter{
+		def:     def,
+		routes:  routes,
+		now:     time.Now,
+		buckets: map[bucketKey]*bucket{},
+	}
+}
+
+// Allow takes a token from client's bucket for route.
+func (l *Limiter) Allow(route, client string) Decision {
+	limit := l.limit(route)
+	if limit.Unlimited() {
+		return Decision{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	key := bucketKey{route: route, client: client}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	d := Decision{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((float64(limit.Burst) - b.tokens) / limit.Rate)
+
+	return d
+}
+
+// Run forgets idle clients every interval until ctx is done, so the buckets of
+// clients that have gone away do not accumulate.
+func (l *Limiter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.sweep()
+		}
+	}
+}
+
+// sweep drops every bucket that has refilled completely, since a new bucket
+// would be identical.
+func (l *Limiter) sweep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for key, b := range l.buckets {
+		limit := l.limit(key.route)
+		if b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) limit(route string) Limit {
+	if limit, ok := l.routes[route]; ok {
+		return limit
+	}
+	return l.def
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeClock is a time source the test moves by hand.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(def Limit, routes map[string]Limit) (*Limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(def, routes)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestAllow(t *testing.T) {
+	l, clock := newTestLimiter(Limit{Rate: 1, Burst: 2}, map[string]Limit{
+		"/v1/search": {Rate: 10, Burst: 1},
+		"/healthz":   {},
+	})
+
+	// The burst is available straight away, then the bucket is empty.
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, l.Allow("/v1/list-races", "a"))
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, l.Allow("/v1/list-races", "a"))
+	assert.Equal(t, Decision{Limit: 2, Reset: 2 * time.Second, RetryAfter: time.Second}, l.Allow("/v1/list-races", "a"))
+
+	// Other clients and routes have their own buckets.
+	assert.True(t, l.Allow("/v1/list-races", "b").Allowed)
+	assert.True(t, l.Allow("/v1/search", "a").Allowed)
+	assert.False(t, l.Allow("/v1/search", "a").Allowed, "route override has a burst of one")
+
+	// Tokens come back at the configured rate.
+	clock.advance(500 * time.Millisecond)
+	assert.False(t, l.Allow("/v1/list-races", "a").Allowed)
+	clock.advance(500 * time.Millisecond)
+	assert.True(t, l.Allow("/v1/list-races", "a").Allowed)
+
+	// An unlimited route is never refused.
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.Allow("/healthz", "a").Allowed)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l, clock := newTestLimiter(Limit{Rate: 1, Burst: 2}, nil)
+	l.Allow("/v1/list-races", "a")
+	l.Allow("/v1/list-races", "b")
+	l.Allow("/v1/list-races", "b")
+
+	clock.advance(time.Second)
+	l.sweep()
+	assert.Len(t, l.buckets, 1, "a has refilled and is forgotten, b has not")
+
+	clock.advance(time.Second)
+	l.sweep()
+	assert.Empty(t, l.buckets)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	trusted, err := ParsePrefixes("10.0.0.0/8, ::1/128")
+	if !assert.NoError(t, err) {
+		return
+	}
+	keys := ParseKeys("k1, ,k2")
+
+	tests := []struct {
+		name       string
+		peer       string
+		md         metadata.MD
+		wantClient string
+	}{
+		{name: "direct caller", peer: "192.0.2.7:5000", wantClient: "ip:192.0.2.7"},
+		{name: "api key", peer: "192.0.2.7:5000", md: metadata.Pairs("x-api-key", "k1"), wantClient: "key:k1"},
+		{name: "unknown api key", peer: "192.0.2.7:5000", md: metadata.Pairs("x-api-key", "made-up"), wantClient: "ip:192.0.2.7"},
+		{name: "unknown api key via trusted gateway", peer: "10.1.2.3:5000", md: metadata.Pairs("x-api-key", "made-up", "x-forwarded-for", "198.51.100.4"), wantClient: "ip:198.51.100.4"},
+		{name: "forwarded by trusted gateway", peer: "10.1.2.3:5000", md: metadata.Pairs("x-forwarded-for", "203.0.113.9, 198.51.100.4"), wantClient: "ip:198.51.100.4"},
+		{name: "forwarded by untrusted peer", peer: "192.0.2.7:5000", md: metadata.Pairs("x-forwarded-for", "198.51.100.4"), wantClient: "ip:192.0.2.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: net.TCPAddrFromAddrPort(netip.MustParseAddrPort(tt.peer))})
+			assert.Equal(t, tt.wantClient, grpcClient(ctx, keys, trusted))
+		})
+	}
+
+	l, _ := newTestLimiter(Limit{Rate: 1, Burst: 1}, nil)
+	interceptor := UnaryServerInterceptor(l, keys, trusted, "/grpc.health.v1.Health/Check")
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	assert.NoError(t, call("/racing.Racing/ListRaces"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call("/racing.Racing/ListRaces")))
+	assert.NoError(t, call("/grpc.health.v1.Health/Check"))
+	assert.NoError(t, call("/grpc.health.v1.Health/Check"), "exempt methods are never limited")
+}
+
+// fakeStream is a grpc.ServerStream with only a context.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Rate: 1, Burst: 1}, nil)
+	interceptor := StreamServerInterceptor(l, nil, nil, "/grpc.health.v1.Health/Watch")
+	call := func(method string) error {
+		ss := &fakeStream{ctx: context.Background()}
+		return interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: method}, func(any, grpc.ServerStream) error {
+			return nil
+		})
+	}
+
+	assert.NoError(t, call("/racing.Racing/ImportRaces"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call("/racing.Racing/ImportRaces")))
+	assert.NoError(t, call("/grpc.health.v1.Health/Watch"))
+	assert.NoError(t, call("/grpc.health.v1.Health/Watch"), "exempt methods are never limited")
+}
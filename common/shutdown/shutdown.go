@@ -0,0 +1,62 @@
+// Package shutdown drains servers within a deadline when a binary is asked to
+// stop.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ErrTimeout is returned when in-flight requests do not finish draining before
+// the timeout. Callers should exit non-zero so the orchestrator knows work was
+// cut short.
+var ErrTimeout = errors.New("timed out draining in-flight requests")
+
+// OnSignal returns a context that is cancelled on SIGINT or SIGTERM.
+func OnSignal() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// GRPC stops s accepting new connections and waits up to timeout for in-flight
+// RPCs to finish. Any still running after that are cancelled and ErrTimeout is
+// returned.
+func GRPC(s *grpc.Server, timeout time.Duration) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-timer.C:
+		s.Stop()
+		return ErrTimeout
+	}
+}
+
+// HTTP stops s accepting new connections and waits up to timeout for in-flight
+// requests to finish. Any still running after that are closed and ErrTimeout is
+// returned.
+func HTTP(s *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := s.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.Close()
+		return ErrTimeout
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		work    time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "drains in time", work: 10 * time.Millisecond, timeout: time.Second},
+		{name: "times out", work: time.Second, timeout: 10 * time.Millisecond, wantErr: ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tt.work):
+				case <-r.Context().Done():
+				}
+			})}
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if !assert.NoError(t, err) {
+				return
+			}
+			go srv.Serve(ln)
+			go http.Get("http://" + ln.Addr().String())
+			<-started
+
+			assert.Equal(t, tt.wantErr, HTTP(srv, tt.timeout))
+		})
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	tests := []struct {
+		name    string
+		work    time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "drains in time", work: 50 * time.Millisecond, timeout: time.Second},
+		{name: "times out", work: 10 * time.Second, timeout: 50 * time.Millisecond, wantErr: ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every RPC is answered by a handler that works for tt.work, or
+			// until the server cancels it.
+			started := make(chan struct{})
+			srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+				if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+					return err
+				}
+				close(started)
+				select {
+				case <-time.After(tt.work):
+				case <-stream.Context().Done():
+					return stream.Context().Err()
+				}
+				return stream.SendMsg(&emptypb.Empty{})
+			}))
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if !assert.NoError(t, err) {
+				return
+			}
+			go srv.Serve(ln)
+
+			conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			rpcErr := make(chan error, 1)
+			go func() {
+				rpcErr <- conn.Invoke(context.Background(), "/test.Slow/Work", &emptypb.Empty{}, &emptypb.Empty{})
+			}()
+			<-started
+
+			begun := time.Now()
+			assert.Equal(t, tt.wantErr, GRPC(srv, tt.timeout))
+			assert.Less(t, time.Since(begun), tt.timeout+time.Second, "GRPC should return soon after the timeout")
+
+			err = <-rpcErr
+			if tt.wantErr == nil {
+				assert.NoError(t, err, "the in-flight RPC should finish")
+			} else {
+				assert.Error(t, err, "the in-flight RPC should be cut off")
+			}
+		})
+	}
+}
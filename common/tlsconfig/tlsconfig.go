@@ -0,0 +1,227 @@
+// Package tlsconfig builds the TLS configurations used between the api gateway
+// and the backend services. Certificates and CA bundles are re-read from disk
+// when they change, so they can be rotated without a restart.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// DefaultReloadInterval is how often Run checks the files for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// Files names the PEM files making up one side of a TLS connection. CertFile and
+// KeyFile are this side's certificate; CAFile is the bundle used to verify the
+// other side. Any of them may be empty when not needed.
+type Files struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Reloader holds the certificate and CA pool loaded from a set of Files and
+// replaces them when the files change on disk.
+type Reloader struct {
+	files Files
+
+	mu    sync.RWMutex
+	cert  *tls.Certificate
+	pool  *x509.CertPool
+	stamp string
+}
+
+// NewReloader loads files, returning an error if they are missing or invalid.
+func NewReloader(files Files) (*Reloader, error) {
+	r := &Reloader{files: files}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Run checks the files every interval until ctx is done, reloading them when
+// they change. A file that fails to load is logged and the previous
+// certificates stay in use.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if reloaded, err := r.Reload(); err != nil {
+			slog.Error("failed reloading TLS certificates, keeping the current ones", "error", err)
+		} else if reloaded {
+			slog.Info("reloaded TLS certificates")
+		}
+	}
+}
+
+// Reload re-reads the files if any has changed since they were last loaded, and
+// reports whether it did.
+func (r *Reloader) Reload() (bool, error) {
+	stamp, err := r.stampFiles()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := stamp == r.stamp
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if r.files.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+		if err != nil {
+			return false, fmt.Errorf("load certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.files.CAFile != "" {
+		pem, err := os.ReadFile(r.files.CAFile)
+		if err != nil {
+			return false, fmt.Errorf("load CA bundle: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("load CA bundle: no certificates in %s", r.files.CAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool, r.stamp = cert, pool, stamp
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// ServerConfig returns a server configuration presenting the current
+// certificate. When a CA bundle is set, clients must present a certificate
+// signed by it (mutual TLS).
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Built per handshake so new connections pick up reloaded files.
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			if cert == nil {
+				return nil, errors.New("tlsconfig: no server certificate configured")
+			}
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientCredentials returns gRPC credentials that verify the server against
+// the current CA bundle, or the system roots when none is set, and present the
+// current certificate when one is set. serverName overrides the name checked
+// against the server's certificate; empty uses the dialled host, which may be
+// an IP address.
+func (r *Reloader) ClientCredentials(serverName string) credentials.TransportCredentials {
+	return &clientCredentials{r: r, serverName: serverName}
+}
+
+// clientConfig returns a client configuration verifying the server against the
+// CA bundle loaded now.
+func (r *Reloader) clientConfig(serverName string) *tls.Config {
+	_, pool := r.current()
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			if cert == nil {
+				// No certificate; the server decides whether that is acceptable.
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+	}
+}
+
+// clientCredentials builds the TLS configuration afresh for each handshake.
+// A tls.Config captures RootCAs when it is made, so one built up front would
+// never use a reloaded CA bundle. The standard verification is kept, which
+// checks the server against the dialled host whether it is a name or an IP.
+type clientCredentials struct {
+	r          *Reloader
+	serverName string
+}
+
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return credentials.NewTLS(c.r.clientConfig(c.serverName)).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *clientCredentials) ServerHandshake(net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("tlsconfig: client credentials cannot accept connections")
+}
+
+func (c *clientCredentials) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(c.r.clientConfig(c.serverName)).Info()
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	clone := *c
+	return &clone
+}
+
+// OverrideServerName is deprecated in gRPC, but part of the interface.
+func (c *clientCredentials) OverrideServerName(name string) error {
+	c.serverName = name
+	return nil
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.pool
+}
+
+// stampFiles summarises the size and modification time of each file, so a
+// change to any of them can be spotted without reading them.
+func (r *Reloader) stampFiles() (string, error) {
+	var parts []string
+	for _, name := range []string{r.files.CertFile, r.files.KeyFile, r.files.CAFile} {
+		if name == "" {
+			parts = append(parts, "")
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, "|"), nil
+}
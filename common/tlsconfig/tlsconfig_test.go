@@ -0,0 +1,321 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA is a throwaway certificate authority created for a single test.
+type testCA struct {
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	file   string
+	serial int64
+}
+
+func newCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key, file: file, serial: 1}
+}
+
+// issue signs a certificate for commonName, valid for localhost as both a
+// server and a client, and returns its certificate and key files.
+func (ca *testCA) issue(t *testing.T, commonName string) (certFile, keyFile string) {
+	t.Helper()
+	return ca.issueFor(t, commonName, []string{"localhost"}, []net.IP{net.ParseIP("127.0.0.1")})
+}
+
+// issueFor is issue for a certificate valid only for dnsNames and ips.
+func (ca *testCA) issueFor(t *testing.T, commonName string, dnsNames []string, ips []net.IP) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve starts a gRPC server with only the health service, using files for its
+// TLS, and returns its address.
+func serve(t *testing.T, files Files) string {
+	t.Helper()
+
+	r, err := NewReloader(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(r.ServerConfig())))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve(ln)
+	t.Cleanup(s.Stop)
+
+	return ln.Addr().String()
+}
+
+func TestHandshake(t *testing.T) {
+	ca, otherCA := newCA(t, "test CA"), newCA(t, "other CA")
+
+	serverCert, serverKey := ca.issue(t, "racing")
+	addr := serve(t, Files{CertFile: serverCert, KeyFile: serverKey, CAFile: ca.file})
+
+	clientCert, clientKey := ca.issue(t, "api")
+	strangerCert, strangerKey := otherCA.issue(t, "stranger")
+
+	tests := []struct {
+		name       string
+		files      Files
+		serverName string
+		wantErr    bool
+	}{
+		{
+			name:  "mutual TLS",
+			files: Files{CertFile: clientCert, KeyFile: clientKey, CAFile: ca.file},
+		},
+		{
+			name:    "no client certificate",
+			files:   Files{CAFile: ca.file},
+			wantErr: true,
+		},
+		{
+			name:    "client certificate from another CA",
+			files:   Files{CertFile: strangerCert, KeyFile: strangerKey, CAFile: ca.file},
+			wantErr: true,
+		},
+		{
+			name:    "server not signed by the trusted CA",
+			files:   Files{CertFile: clientCert, KeyFile: clientKey, CAFile: otherCA.file},
+			wantErr: true,
+		},
+		{
+			name:       "server name mismatch",
+			files:      Files{CertFile: clientCert, KeyFile: clientKey, CAFile: ca.file},
+			serverName: "sports.internal",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReloader(tt.files)
+			if !assert.NoError(t, err) {
+				return
+			}
+			serverName := tt.serverName
+			if serverName == "" {
+				serverName = "localhost"
+			}
+
+			err = check(t, addr, r.ClientCredentials(serverName))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// check calls the health service at addr over creds.
+func check(t *testing.T, addr string, creds credentials.TransportCredentials) error {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestHandshake_DialByIP(t *testing.T) {
+	ca := newCA(t, "test CA")
+	r, err := NewReloader(Files{CAFile: ca.file})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// A certificate from the trusted CA for some other host must not be
+	// accepted just because the dialled host is an IP, which sends no SNI.
+	otherCert, otherKey := ca.issueFor(t, "unrelated", []string{"unrelated.example"}, nil)
+	addr := serve(t, Files{CertFile: otherCert, KeyFile: otherKey})
+	assert.Error(t, check(t, addr, r.ClientCredentials("")), "certificate for another host")
+	assert.NoError(t, check(t, addr, r.ClientCredentials("unrelated.example")), "server name set explicitly")
+
+	ipCert, ipKey := ca.issueFor(t, "racing", nil, []net.IP{net.ParseIP("127.0.0.1")})
+	addr = serve(t, Files{CertFile: ipCert, KeyFile: ipKey})
+	assert.NoError(t, check(t, addr, r.ClientCredentials("")), "certificate for the dialled IP")
+}
+
+func TestHandshake_ReloadedCA(t *testing.T) {
+	ca, otherCA := newCA(t, "test CA"), newCA(t, "other CA")
+	serverCert, serverKey := ca.issue(t, "racing")
+	addr := serve(t, Files{CertFile: serverCert, KeyFile: serverKey})
+
+	// The client starts out trusting only the other CA.
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	copyFile := func(src string, when time.Time) {
+		b, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(caFile, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(caFile, when, when); err != nil {
+			t.Fatal(err)
+		}
+	}
+	copyFile(otherCA.file, time.Now())
+
+	r, err := NewReloader(Files{CAFile: caFile})
+	if !assert.NoError(t, err) {
+		return
+	}
+	creds := r.ClientCredentials("")
+	assert.Error(t, check(t, addr, creds))
+
+	copyFile(ca.file, time.Now().Add(time.Minute))
+	_, err = r.Reload()
+	assert.NoError(t, err)
+	assert.NoError(t, check(t, addr, creds), "new handshakes use the reloaded CA bundle")
+}
+
+func TestReload(t *testing.T) {
+	ca := newCA(t, "test CA")
+	certFile, keyFile := ca.issue(t, "first")
+
+	r, err := NewReloader(Files{CertFile: certFile, KeyFile: keyFile})
+	if !assert.NoError(t, err) {
+		return
+	}
+	served := func() string {
+		cfg, err := r.ServerConfig().GetConfigForClient(nil)
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		leaf, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "first", served())
+
+	reloaded, err := r.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not reloaded")
+
+	// Rotate the certificate in place, as a secret mount would.
+	rotate := func(newCert, newKey string, when time.Time) {
+		for src, dst := range map[string]string{newCert: certFile, newKey: keyFile} {
+			b, err := os.ReadFile(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(dst, b, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(dst, when, when); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	secondCert, secondKey := ca.issue(t, "second")
+	rotate(secondCert, secondKey, time.Now().Add(time.Minute))
+	reloaded, err = r.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "second", served())
+
+	// A broken rotation keeps the last good certificate.
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "second", served())
+}
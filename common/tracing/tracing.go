@@ -0,0 +1,100 @@
+// Package tracing sets up OpenTelemetry tracing for the gateway and the gRPC
+// services.
+//
+// Setup installs the global tracer provider and the W3C trace-context
+// propagator; ServerHandler and ClientHandler trace gRPC calls with them, so a
+// trace started at the gateway carries on into racing and sports.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc"
+)
+
+// Exporters accepted by Setup.
+const (
+	// ExporterNone records no spans, but still passes incoming trace context
+	// on to the services called.
+	ExporterNone = "none"
+	// ExporterStdout writes each span to standard output as JSON.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over OTLP/gRPC.
+	ExporterOTLP = "otlp"
+)
+
+// Setup installs the global tracer provider for service, sending spans to
+// exporter, and the W3C trace-context propagator. endpoint is the collector URL
+// for ExporterOTLP, such as http://localhost:4317; an http scheme sends spans
+// in plaintext.
+//
+// The returned function flushes any buffered spans and must be called before
+// the binary exits.
+func Setup(ctx context.Context, service, exporter, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exp, err := newExporter(ctx, exporter, endpoint, os.Stdout)
+	if err != nil || exp == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	tp, err := newProvider(service, exp)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// newExporter returns the named exporter, or nil for ExporterNone.
+func newExporter(ctx context.Context, exporter, endpoint string, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	case ExporterOTLP:
+		return otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(endpoint))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+}
+
+func newProvider(service string, exp sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(service)))
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// ServerHandler traces the RPCs a gRPC server handles, continuing the trace
+// named in their metadata. Health checks are not traced.
+func ServerHandler() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))))
+}
+
+// ClientHandler traces the RPCs a gRPC client makes and passes the trace on to
+// the server in their metadata. Health checks are not traced.
+func ClientHandler() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))))
+}
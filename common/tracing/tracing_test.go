@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestNewExporter(t *testing.T) {
+	exp, err := newExporter(context.Background(), ExporterNone, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, exp)
+
+	_, err = newExporter(context.Background(), "jaeger", "", nil)
+	assert.EqualError(t, err, `unknown trace exporter "jaeger"`)
+
+	var out bytes.Buffer
+	exp, err = newExporter(context.Background(), ExporterStdout, "", &out)
+	require.NoError(t, err)
+	tp, err := newProvider("racing", exp)
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "List")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	assert.Contains(t, out.String(), `"Name":"List"`)
+	assert.Contains(t, out.String(), `"Value":"racing"`)
+}
+
+// TestPropagation checks that a call made inside a span is handled in the same
+// trace on the server.
+func TestPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(ServerHandler(), grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		var msg emptypb.Empty
+		if err := stream.RecvMsg(&msg); err != nil {
+			return err
+		}
+		return stream.SendMsg(&msg)
+	}))
+	go func() { _ = s.Serve(ln) }()
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()), ClientHandler())
+	require.NoError(t, err)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, conn.Invoke(ctx, "/test.Echo/Ping", &emptypb.Empty{}, &emptypb.Empty{}))
+	parent.End()
+
+	// The server span ends once the handler returns; stopping waits for that.
+	conn.Close()
+	s.GracefulStop()
+
+	var server sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		}
+	}
+	require.NotNil(t, server, "server span")
+	assert.Equal(t, "test.Echo/Ping", server.Name())
+	assert.Equal(t, parent.SpanContext().TraceID(), server.SpanContext().TraceID())
+}
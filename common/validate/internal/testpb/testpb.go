@@ -0,0 +1,4 @@
+// Package testpb holds the messages used to test the validate package.
+package testpb
+
+//go:generate protoc -I ../../../proto -I .. --go_out=.. --go_opt=paths=source_relative testpb/testpb.proto
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.2
+// source: testpb/testpb.proto
+
+package testpb
+
+import (
+	_ "github.com/SylvanSol/Entain_Test/common/proto/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Colour int32
+
+const (
+	Colour_COLOUR_UNSPECIFIED Colour = 0
+	Colour_COLOUR_RED         Colour = 1
+)
+
+// Enum value maps for Colour.
+var (
+	Colour_name = map[int32]string{
+		0: "COLOUR_UNSPECIFIED",
+		1: "COLOUR_RED",
+	}
+	Colour_value = map[string]int32{
+		"COLOUR_UNSPECIFIED": 0,
+		"COLOUR_RED":         1,
+	}
+)
+
+func (x Colour) Enum() *Colour {
+	p := new(Colour)
+	*p = x
+	return p
+}
+
+func (x Colour) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Colour) Descriptor() protoreflect.EnumDescriptor {
+	return file_testpb_testpb_proto_enumTypes[0].Descriptor()
+}
+
+func (Colour) Type() protoreflect.EnumType {
+	return &file_testpb_testpb_proto_enumTypes[0]
+}
+
+func (x Colour) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Colour.Descriptor instead.
+func (Colour) EnumDescriptor() ([]byte, []int) {
+	return file_testpb_testpb_proto_rawDescGZIP(), []int{0}
+}
+
+// Request exercises each validation rule.
+type Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ids           []int64                `protobuf:"varint,2,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Direction     string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Colour        Colour                 `protobuf:"varint,5,opt,name=colour,proto3,enum=testpb.Colour" json:"colour,omitempty"`
+	Nested        *Nested                `protobuf:"bytes,6,opt,name=nested,proto3" json:"nested,omitempty"`
+	Items         []*Nested              `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	Unchecked     int64                  `protobuf:"varint,8,opt,name=unchecked,proto3" json:"unchecked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	mi := &file_testpb_testpb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_testpb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_testpb_testpb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Request) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Request) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *Request) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Request) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Request) GetColour() Colour {
+	if x != nil {
+		return x.Colour
+	}
+	return Colour_COLOUR_UNSPECIFIED
+}
+
+func (x *Request) GetNested() *Nested {
+	if x != nil {
+		return x.Nested
+	}
+	return nil
+}
+
+func (x *Request) GetItems() []*Nested {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Request) GetUnchecked() int64 {
+	if x != nil {
+		return x.Unchecked
+	}
+	return 0
+}
+
+type Nested struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nested) Reset() {
+	*x = Nested{}
+	mi := &file_testpb_testpb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nested) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nested) ProtoMessage() {}
+
+func (x *Nested) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_testpb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nested.ProtoReflect.Descriptor instead.
+func (*Nested) Descriptor() ([]byte, []int) {
+	return file_testpb_testpb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Nested) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_testpb_testpb_proto protoreflect.FileDescriptor
+
+const file_testpb_testpb_proto_rawDesc = "" +
+	"\n" +
+	"\x13testpb/testpb.proto\x12\x06testpb\x1a\x17validate/validate.proto\"\xaa\x02\n" +
+	"\aRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\x03B\x06\xc2\xf3\x18\x02\x10\x01R\x02id\x12\x1a\n" +
+	"\x03ids\x18\x02 \x03(\x03B\b\xc2\xf3\x18\x04\x10\x01 \x03R\x03ids\x12-\n" +
+	"\tdirection\x18\x03 \x01(\tB\x0f\xc2\xf3\x18\v*\x03asc*\x04descR\tdirection\x12 \n" +
+	"\x06reason\x18\x04 \x01(\tB\b\xc2\xf3\x18\x04\b\x018\n" +
+	"R\x06reason\x12.\n" +
+	"\x06colour\x18\x05 \x01(\x0e2\x0e.testpb.ColourB\x06\xc2\xf3\x18\x020\x01R\x06colour\x12&\n" +
+	"\x06nested\x18\x06 \x01(\v2\x0e.testpb.NestedR\x06nested\x12$\n" +
+	"\x05items\x18\a \x03(\v2\x0e.testpb.NestedR\x05items\x12\x1c\n" +
+	"\tunchecked\x18\b \x01(\x03R\tunchecked\"&\n" +
+	"\x06Nested\x12\x1c\n" +
+	"\x05count\x18\x01 \x01(\rB\x06\xc2\xf3\x18\x02\x18\x05R\x05count*0\n" +
+	"\x06Colour\x12\x16\n" +
+	"\x12COLOUR_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"COLOUR_RED\x10\x01BBZ@github.com/SylvanSol/Entain_Test/common/validate/internal/testpbb\x06proto3"
+
+var (
+	file_testpb_testpb_proto_rawDescOnce sync.Once
+	file_testpb_testpb_proto_rawDescData []byte
+)
+
+func file_testpb_testpb_proto_rawDescGZIP() []byte {
+	file_testpb_testpb_proto_rawDescOnce.Do(func() {
+		file_testpb_testpb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_testpb_testpb_proto_rawDesc), len(file_testpb_testpb_proto_rawDesc)))
+	})
+	return file_testpb_testpb_proto_rawDescData
+}
+
+var file_testpb_testpb_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_testpb_testpb_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_testpb_testpb_proto_goTypes = []any{
+	(Colour)(0),     // 0: testpb.Colour
+	(*Request)(nil), // 1: testpb.Request
+	(*Nested)(nil),  // 2: testpb.Nested
+}
+var file_testpb_testpb_proto_depIdxs = []int32{
+	0, // 0: testpb.Request.colour:type_name -> testpb.Colour
+	2, // 1: testpb.Request.nested:type_name -> testpb.Nested
+	2, // 2: testpb.Request.items:type_name -> testpb.Nested
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_testpb_testpb_proto_init() }
+func file_testpb_testpb_proto_init() {
+	if File_testpb_testpb_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_testpb_testpb_proto_rawDesc), len(file_testpb_testpb_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_testpb_testpb_proto_goTypes,
+		DependencyIndexes: file_testpb_testpb_proto_depIdxs,
+		EnumInfos:         file_testpb_testpb_proto_enumTypes,
+		MessageInfos:      file_testpb_testpb_proto_msgTypes,
+	}.Build()
+	File_testpb_testpb_proto = out.File
+	file_testpb_testpb_proto_goTypes = nil
+	file_testpb_testpb_proto_depIdxs = nil
+}
@@ -0,0 +1,178 @@
+// Package validate enforces the validation rules declared on request fields with
+// the (validate.rules) option from common/proto/validate/validate.proto.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	validatepb "github.com/SylvanSol/Entain_Test/common/proto/validate"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// UnaryServerInterceptor rejects any request that breaks its declared rules with
+// an InvalidArgument error listing every violation, before the handler runs.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if violations := Message(msg); len(violations) > 0 {
+				return nil, apierr.InvalidArgument(violations...)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor checks each message a client streams in against its
+// declared rules. RecvMsg fails with an InvalidArgument error listing every
+// violation when a message breaks them, and the handler's returning that error
+// ends the RPC with it.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ss})
+	}
+}
+
+// validatingStream validates the messages received on a grpc.ServerStream.
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		if violations := Message(msg); len(violations) > 0 {
+			return apierr.InvalidArgument(violations...)
+		}
+	}
+	return nil
+}
+
+// Message checks msg, and every message nested within it, against the declared
+// rules and returns the violations found. Field paths use the proto field names,
+// e.g. "filter.meeting_ids[1]".
+func Message(msg proto.Message) []apierr.FieldViolation {
+	var violations []apierr.FieldViolation
+	validateMessage(msg.ProtoReflect(), "", &violations)
+	return violations
+}
+
+func validateMessage(m protoreflect.Message, prefix string, violations *[]apierr.FieldViolation) {
+	fields := m.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+
+		if rules := fieldRules(fd); rules != nil {
+			validateField(m, fd, rules, path, violations)
+		}
+
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !m.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				validateMessage(list.Get(j).Message(), fmt.Sprintf("%s[%d].", path, j), violations)
+			}
+			continue
+		}
+		validateMessage(m.Get(fd).Message(), path+".", violations)
+	}
+}
+
+func fieldRules(fd protoreflect.FieldDescriptor) *validatepb.FieldRules {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil || !proto.HasExtension(opts, validatepb.E_Rules) {
+		return nil
+	}
+
+	rules, _ := proto.GetExtension(opts, validatepb.E_Rules).(*validatepb.FieldRules)
+	return rules
+}
+
+func validateField(m protoreflect.Message, fd protoreflect.FieldDescriptor, rules *validatepb.FieldRules, path string, violations *[]apierr.FieldViolation) {
+	add := func(field, description string) {
+		*violations = append(*violations, apierr.FieldViolation{Field: field, Description: description})
+	}
+
+	if fd.IsList() {
+		list := m.Get(fd).List()
+		if rules.Required && list.Len() == 0 {
+			add(path, "must not be empty")
+		}
+		if rules.MaxItems != nil && uint32(list.Len()) > rules.GetMaxItems() {
+			add(path, fmt.Sprintf("must contain at most %d items", rules.GetMaxItems()))
+		}
+		for j := 0; j < list.Len(); j++ {
+			if description := checkValue(fd, list.Get(j), rules); description != "" {
+				add(fmt.Sprintf("%s[%d]", path, j), description)
+			}
+		}
+		return
+	}
+
+	if rules.Required && (!m.Has(fd) || fd.Kind() == protoreflect.StringKind && strings.TrimSpace(m.Get(fd).String()) == "") {
+		add(path, "is required")
+		return
+	}
+	if description := checkValue(fd, m.Get(fd), rules); description != "" {
+		add(path, description)
+	}
+}
+
+// checkValue applies the value rules to a single (non-list) value and returns a
+// description of the first one broken, or "" if it is valid.
+func checkValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, rules *validatepb.FieldRules) string {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Int64Kind,
+		protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		return checkRange(v.Int(), rules)
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind,
+		protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return checkRange(int64(v.Uint()), rules)
+	case protoreflect.StringKind:
+		return checkString(v.String(), rules)
+	case protoreflect.EnumKind:
+		if rules.DefinedOnly && fd.Enum().Values().ByNumber(v.Enum()) == nil {
+			return fmt.Sprintf("unknown %s value %d", fd.Enum().Name(), v.Enum())
+		}
+	}
+	return ""
+}
+
+func checkRange(n int64, rules *validatepb.FieldRules) string {
+	if rules.Min != nil && n < rules.GetMin() {
+		return fmt.Sprintf("must be at least %d", rules.GetMin())
+	}
+	if rules.Max != nil && n > rules.GetMax() {
+		return fmt.Sprintf("must be at most %d", rules.GetMax())
+	}
+	return ""
+}
+
+func checkString(s string, rules *validatepb.FieldRules) string {
+	if rules.MaxLen != nil && uint32(utf8.RuneCountInString(s)) > rules.GetMaxLen() {
+		return fmt.Sprintf("must be at most %d characters", rules.GetMaxLen())
+	}
+	if len(rules.In) == 0 || s == "" {
+		return ""
+	}
+	for _, allowed := range rules.In {
+		if strings.EqualFold(s, allowed) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be one of %s", strings.Join(rules.In, ", "))
+}
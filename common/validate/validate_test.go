@@ -0,0 +1,167 @@
+package validate
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/validate/internal/testpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMessage(t *testing.T) {
+	// valid returns a request that passes every rule.
+	valid := func() *testpb.Request {
+		return &testpb.Request{Id: 1, Ids: []int64{1, 2}, Direction: "asc", Reason: "ok"}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(r *testpb.Request)
+		want   []apierr.FieldViolation
+	}{
+		{
+			name:   "valid",
+			mutate: func(r *testpb.Request) {},
+		},
+		{
+			name:   "below min",
+			mutate: func(r *testpb.Request) { r.Id = 0 },
+			want:   []apierr.FieldViolation{{Field: "id", Description: "must be at least 1"}},
+		},
+		{
+			name:   "list element below min",
+			mutate: func(r *testpb.Request) { r.Ids = []int64{1, -4} },
+			want:   []apierr.FieldViolation{{Field: "ids[1]", Description: "must be at least 1"}},
+		},
+		{
+			name:   "too many items",
+			mutate: func(r *testpb.Request) { r.Ids = []int64{1, 2, 3, 4} },
+			want:   []apierr.FieldViolation{{Field: "ids", Description: "must contain at most 3 items"}},
+		},
+		{
+			name:   "string not in set",
+			mutate: func(r *testpb.Request) { r.Direction = "sideways" },
+			want:   []apierr.FieldViolation{{Field: "direction", Description: "must be one of asc, desc"}},
+		},
+		{
+			name:   "string in set ignores case",
+			mutate: func(r *testpb.Request) { r.Direction = "DESC" },
+		},
+		{
+			name:   "empty string skips in",
+			mutate: func(r *testpb.Request) { r.Direction = "" },
+		},
+		{
+			name:   "required missing",
+			mutate: func(r *testpb.Request) { r.Reason = "" },
+			want:   []apierr.FieldViolation{{Field: "reason", Description: "is required"}},
+		},
+		{
+			name:   "required whitespace only",
+			mutate: func(r *testpb.Request) { r.Reason = " \t\n" },
+			want:   []apierr.FieldViolation{{Field: "reason", Description: "is required"}},
+		},
+		{
+			name:   "too long",
+			mutate: func(r *testpb.Request) { r.Reason = "far too long a reason" },
+			want:   []apierr.FieldViolation{{Field: "reason", Description: "must be at most 10 characters"}},
+		},
+		{
+			name:   "undefined enum",
+			mutate: func(r *testpb.Request) { r.Colour = testpb.Colour(7) },
+			want:   []apierr.FieldViolation{{Field: "colour", Description: "unknown Colour value 7"}},
+		},
+		{
+			name:   "nested message",
+			mutate: func(r *testpb.Request) { r.Nested = &testpb.Nested{Count: 6} },
+			want:   []apierr.FieldViolation{{Field: "nested.count", Description: "must be at most 5"}},
+		},
+		{
+			name:   "repeated nested message",
+			mutate: func(r *testpb.Request) { r.Items = []*testpb.Nested{{Count: 1}, {Count: 9}} },
+			want:   []apierr.FieldViolation{{Field: "items[1].count", Description: "must be at most 5"}},
+		},
+		{
+			name: "reports every violation",
+			mutate: func(r *testpb.Request) {
+				r.Id = -1
+				r.Reason = ""
+			},
+			want: []apierr.FieldViolation{
+				{Field: "id", Description: "must be at least 1"},
+				{Field: "reason", Description: "is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(req)
+			assert.Equal(t, tt.want, Message(req))
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return req, nil
+	}
+
+	_, err := interceptor(context.Background(), &testpb.Request{}, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, called, "handler should not run for an invalid request")
+
+	_, err = interceptor(context.Background(), &testpb.Request{Id: 1, Reason: "ok"}, &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+	assert.True(t, called, "handler should run for a valid request")
+}
+
+// fakeStream is a grpc.ServerStream that receives msgs in turn.
+type fakeStream struct {
+	grpc.ServerStream
+	msgs []proto.Message
+}
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.msgs[0])
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor()
+	var received int
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		for {
+			var req testpb.Request
+			if err := ss.RecvMsg(&req); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			received++
+		}
+	}
+
+	ss := &fakeStream{msgs: []proto.Message{&testpb.Request{Id: 1, Reason: "ok"}, &testpb.Request{}, &testpb.Request{Id: 2, Reason: "ok"}}}
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 1, received, "the stream should stop at the invalid message")
+
+	ss = &fakeStream{msgs: []proto.Message{&testpb.Request{Id: 1, Reason: "ok"}, &testpb.Request{Id: 2, Reason: "ok"}}}
+	assert.NoError(t, interceptor(nil, ss, &grpc.StreamServerInfo{}, handler))
+	assert.Equal(t, 3, received)
+}
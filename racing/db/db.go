@@ -3,14 +3,19 @@ package db
 import (
 	"time"
 
+	"github.com/SylvanSol/Entain_Test/common/fts"
+
 	"syreclabs.com/go/faker"
 )
 
 func (r *racesRepo) seed() error {
-	statement, err := r.db.Prepare(`CREATE TABLE IF NOT EXISTS races (id INTEGER PRIMARY KEY, meeting_id INTEGER, name TEXT, number INTEGER, visible INTEGER, advertised_start_time DATETIME)`)
+	statement, err := r.db.Prepare(`CREATE TABLE IF NOT EXISTS races (id INTEGER PRIMARY KEY, meeting_id INTEGER, name TEXT, number INTEGER, visible INTEGER, advertised_start_time DATETIME, abandoned INTEGER NOT NULL DEFAULT 0, delayed INTEGER NOT NULL DEFAULT 0)`)
 	if err == nil {
 		_, err = statement.Exec()
 	}
+	if err != nil || r.noSeed {
+		return err
+	}
 
 	for i := 1; i <= 100; i++ {
 		statement, err = r.db.Prepare(`INSERT OR IGNORE INTO races(id, meeting_id, name, number, visible, advertised_start_time) VALUES (?,?,?,?,?,?)`)
@@ -28,3 +33,110 @@ func (r *racesRepo) seed() error {
 
 	return err
 }
+
+// migrate brings a races database created by an older build up to the current
+// schema. It is safe to run repeatedly.
+func (r *racesRepo) migrate() error {
+	if _, err := r.db.Exec(`CREATE TABLE IF NOT EXISTS race_schedule_history (id INTEGER PRIMARY KEY AUTOINCREMENT, race_id INTEGER NOT NULL, change_type INTEGER NOT NULL, previous_start_time DATETIME, new_start_time DATETIME, reason TEXT, changed_at DATETIME)`); err != nil {
+		return err
+	}
+
+	if err := r.ensureColumn("races", "abandoned", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := r.ensureColumn("races", "delayed", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	if err := r.migrateImport(); err != nil {
+		return err
+	}
+
+	// The search index covers meeting names, so it needs the meetings table.
+	return r.migrateSearch()
+}
+
+// migrateImport creates the meetings table and the races columns that imports
+// match meetings and races on. The provider's IDs are unique per provider;
+// races that were not imported leave them NULL.
+func (r *racesRepo) migrateImport() error {
+	if _, err := r.db.Exec(`CREATE TABLE IF NOT EXISTS meetings (id INTEGER PRIMARY KEY, provider TEXT NOT NULL, external_id TEXT NOT NULL, name TEXT NOT NULL, UNIQUE (provider, external_id))`); err != nil {
+		return err
+	}
+
+	if err := r.ensureColumn("races", "provider", "TEXT"); err != nil {
+		return err
+	}
+
+	if err := r.ensureColumn("races", "external_id", "TEXT"); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS races_external_id ON races (provider, external_id)`)
+	return err
+}
+
+// migrateSearch creates the races_fts full-text index over race and meeting
+// (venue) names, and the triggers that keep it in step with the races and
+// meetings tables. The index is rebuilt from scratch at each start, which also
+// replaces any index an older build left. Without FTS5 only the triggers are
+// dropped, so writes still work against a database indexed by an FTS5 build.
+func (r *racesRepo) migrateSearch() error {
+	statements := []string{
+		`DROP TRIGGER IF EXISTS races_fts_before_update`,
+		`DROP TRIGGER IF EXISTS races_fts_before_delete`,
+		`DROP TRIGGER IF EXISTS races_fts_after_update`,
+		`DROP TRIGGER IF EXISTS races_fts_after_delete`,
+		`DROP TRIGGER IF EXISTS races_fts_after_insert`,
+		`DROP TRIGGER IF EXISTS races_fts_meeting_after_insert`,
+		`DROP TRIGGER IF EXISTS races_fts_meeting_after_update`,
+	}
+	if fts.Enabled {
+		statements = append(statements,
+			`DROP TABLE IF EXISTS races_fts`,
+			// The index keeps its own copy of the text, as the meeting name is
+			// not a column of races.
+			`CREATE VIRTUAL TABLE races_fts USING fts5(name, meeting, tokenize='unicode61')`,
+			`CREATE TRIGGER races_fts_after_insert AFTER INSERT ON races BEGIN INSERT INTO races_fts(rowid, name, meeting) VALUES (new.id, new.name, (SELECT name FROM meetings WHERE id = new.meeting_id)); END`,
+			`CREATE TRIGGER races_fts_after_delete AFTER DELETE ON races BEGIN DELETE FROM races_fts WHERE rowid = old.id; END`,
+			`CREATE TRIGGER races_fts_after_update AFTER UPDATE OF name, meeting_id ON races BEGIN DELETE FROM races_fts WHERE rowid = old.id; INSERT INTO races_fts(rowid, name, meeting) VALUES (new.id, new.name, (SELECT name FROM meetings WHERE id = new.meeting_id)); END`,
+			`CREATE TRIGGER races_fts_meeting_after_insert AFTER INSERT ON meetings BEGIN UPDATE races_fts SET meeting = new.name WHERE rowid IN (SELECT id FROM races WHERE meeting_id = new.id); END`,
+			`CREATE TRIGGER races_fts_meeting_after_update AFTER UPDATE OF name ON meetings BEGIN UPDATE races_fts SET meeting = new.name WHERE rowid IN (SELECT id FROM races WHERE meeting_id = new.id); END`,
+			`INSERT INTO races_fts(rowid, name, meeting) SELECT races.id, races.name, meetings.name FROM races LEFT JOIN meetings ON meetings.id = races.meeting_id`,
+		)
+	}
+
+	for _, statement := range statements {
+		if _, err := r.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureColumn adds column to table unless it already exists.
+func (r *racesRepo) ensureColumn(table, column, definition string) error {
+	rows, err := r.db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition)
+	return err
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+)
+
+// ErrUnknownField is returned when a read mask names a field Race does not have.
+var ErrUnknownField = errors.New("unknown field")
+
+// raceRow is a row of the races table as read into a Race.
+type raceRow struct {
+	race               racing.Race
+	advertisedStart    time.Time
+	abandoned, delayed bool
+}
+
+// raceColumn is a column of the races table and where it is scanned to.
+type raceColumn struct {
+	name string
+	dest func(*raceRow) interface{}
+}
+
+// raceColumns are the columns of the races table a Race is read from, in the
+// order they are selected.
+var raceColumns = []raceColumn{
+	{"id", func(r *raceRow) interface{} { return &r.race.Id }},
+	{"meeting_id", func(r *raceRow) interface{} { return &r.race.MeetingId }},
+	{"name", func(r *raceRow) interface{} { return &r.race.Name }},
+	{"number", func(r *raceRow) interface{} { return &r.race.Number }},
+	{"visible", func(r *raceRow) interface{} { return &r.race.Visible }},
+	{"advertised_start_time", func(r *raceRow) interface{} { return &r.advertisedStart }},
+	{"abandoned", func(r *raceRow) interface{} { return &r.abandoned }},
+	{"delayed", func(r *raceRow) interface{} { return &r.delayed }},
+}
+
+// raceFieldColumns maps each Race field to the columns it is derived from.
+var raceFieldColumns = map[string][]string{
+	"id":                    {"id"},
+	"meeting_id":            {"meeting_id"},
+	"name":                  {"name"},
+	"number":                {"number"},
+	"visible":               {"visible"},
+	"advertised_start_time": {"advertised_start_time"},
+	"status":                {"advertised_start_time", "abandoned", "delayed"},
+}
+
+// RaceFields is the set of Race fields a read returns. The nil RaceFields
+// returns every field.
+type RaceFields map[string]bool
+
+// ParseReadMask returns the fields named by mask. An empty mask, or one holding
+// the wildcard "*", names every field.
+func ParseReadMask(mask *fieldmaskpb.FieldMask) (RaceFields, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	fields := RaceFields{}
+	for _, path := range mask.GetPaths() {
+		if path == "*" {
+			return nil, nil
+		}
+		if _, ok := raceFieldColumns[path]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownField, path)
+		}
+		fields[path] = true
+	}
+	return fields, nil
+}
+
+// Has reports whether field is in the set.
+func (f RaceFields) Has(field string) bool {
+	return f == nil || f[field]
+}
+
+// With returns the set with field added.
+func (f RaceFields) With(field string) RaceFields {
+	if f.Has(field) {
+		return f
+	}
+	with := RaceFields{field: true}
+	for name := range f {
+		with[name] = true
+	}
+	return with
+}
+
+// Apply clears the fields of race that are not in the set.
+func (f RaceFields) Apply(race *racing.Race) {
+	if f == nil {
+		return
+	}
+	m := race.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if !f[string(fields.Get(i).Name())] {
+			m.Clear(fields.Get(i))
+		}
+	}
+}
+
+// columns returns the columns to select for the set, in raceColumns order.
+func (f RaceFields) columns() []raceColumn {
+	needed := map[string]bool{}
+	for field, columns := range raceFieldColumns {
+		if f.Has(field) {
+			for _, column := range columns {
+				needed[column] = true
+			}
+		}
+	}
+
+	var columns []raceColumn
+	for _, column := range raceColumns {
+		if needed[column.name] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// selectList returns the SELECT list for columns.
+func selectList(columns []raceColumn) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// scan reads the current row into a Race holding the fields in the set, which
+// must be those columns were chosen for.
+func (f RaceFields) scan(rows interface{ Scan(...interface{}) error }, columns []raceColumn) (*racing.Race, error) {
+	var row raceRow
+	dest := make([]interface{}, len(columns))
+	for i, column := range columns {
+		dest[i] = column.dest(&row)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if f.Has("advertised_start_time") {
+		row.race.AdvertisedStartTime = timestamppb.New(row.advertisedStart)
+	}
+	if f.Has("status") {
+		row.race.Status = deriveStatus(row.advertisedStart, row.abandoned, row.delayed)
+	}
+	return &row.race, nil
+}
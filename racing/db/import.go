@@ -0,0 +1,254 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+)
+
+// importedRaceFields are the race fields an import sets, in the order
+// importedRace.values renders them.
+var importedRaceFields = []string{"meeting_id", "name", "number", "visible", "advertised_start_time"}
+
+// importedRace is the part of a race an import sets.
+type importedRace struct {
+	meetingID int64
+	name      string
+	number    int64
+	visible   bool
+	start     time.Time
+}
+
+// values renders r's fields as text, in the order of importedRaceFields. A nil
+// r renders as empty values, as a race has before it is created.
+func (r *importedRace) values() []string {
+	if r == nil {
+		return make([]string, len(importedRaceFields))
+	}
+	return []string{
+		strconv.FormatInt(r.meetingID, 10),
+		r.name,
+		strconv.FormatInt(r.number, 10),
+		strconv.FormatBool(r.visible),
+		r.startText(),
+	}
+}
+
+// startText renders r's start time as it is stored.
+func (r *importedRace) startText() string {
+	return r.start.UTC().Format(time.RFC3339)
+}
+
+// changesFrom lists the fields of r that differ from those of from.
+func (r *importedRace) changesFrom(from *importedRace) []*racing.FieldChange {
+	var (
+		changes       []*racing.FieldChange
+		before, after = from.values(), r.values()
+	)
+	for i, field := range importedRaceFields {
+		if before[i] != after[i] {
+			changes = append(changes, &racing.FieldChange{Field: field, OldValue: before[i], NewValue: after[i]})
+		}
+	}
+	return changes
+}
+
+// Import creates and updates the meetings and races in rows within a single
+// transaction. Meetings and races are matched on the provider's IDs; a meeting
+// is only looked at for the first row naming it. The rows must already be
+// valid.
+//
+// A new start time for an existing race is a schedule change, recorded in its
+// history. A race a trader has delayed or abandoned keeps its start time, with
+// a warning, though its other fields are still updated.
+func (r *racesRepo) Import(ctx context.Context, provider string, rows []*racing.ImportRaceRow, dryRun bool) (_ *racing.ImportRacesResponse, err error) {
+	query := getRaceQueries()[importRace]
+	ctx, done := r.startQuery(ctx, "import", "racesRepo.Import", query)
+	defer func() { done(&err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	im := &importer{
+		tx:       tx,
+		provider: provider,
+		meetings: make(map[string]int64),
+		resp:     &racing.ImportRacesResponse{Summary: &racing.ImportSummary{Rows: int64(len(rows))}},
+	}
+	for _, row := range rows {
+		meetingID, ok := im.meetings[row.MeetingExternalId]
+		if !ok {
+			if meetingID, err = im.meeting(ctx, row); err != nil {
+				return nil, err
+			}
+			im.meetings[row.MeetingExternalId] = meetingID
+		}
+
+		if err := im.race(ctx, row, meetingID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	return im.resp, nil
+}
+
+// importer applies the rows of one import within its transaction.
+type importer struct {
+	tx       *sql.Tx
+	provider string
+	// meetings maps the provider's IDs of the meetings seen so far to ours.
+	meetings map[string]int64
+	// resp gathers the changes, warnings and summary.
+	resp *racing.ImportRacesResponse
+}
+
+// meeting creates or renames the meeting row belongs to and returns its ID.
+func (im *importer) meeting(ctx context.Context, row *racing.ImportRaceRow) (int64, error) {
+	var (
+		id   int64
+		name string
+	)
+	err := im.tx.QueryRowContext(ctx, getRaceQueries()[importMeeting], im.provider, row.MeetingExternalId).Scan(&id, &name)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := im.tx.QueryRowContext(ctx, getRaceQueries()[importNextMeetingID]).Scan(&id); err != nil {
+			return 0, err
+		}
+		if _, err := im.tx.ExecContext(ctx,
+			`INSERT INTO meetings(id, provider, external_id, name) VALUES (?,?,?,?)`,
+			id, im.provider, row.MeetingExternalId, row.MeetingName,
+		); err != nil {
+			return 0, err
+		}
+		im.resp.Summary.MeetingsCreated++
+		im.resp.Changes = append(im.resp.Changes, &racing.ImportChange{
+			Row:        row.Row,
+			Action:     racing.ImportAction_IMPORT_ACTION_CREATE,
+			Kind:       "meeting",
+			Id:         id,
+			ExternalId: row.MeetingExternalId,
+			Fields:     []*racing.FieldChange{{Field: "name", NewValue: row.MeetingName}},
+		})
+	case err != nil:
+		return 0, err
+	case name != row.MeetingName:
+		if _, err := im.tx.ExecContext(ctx, `UPDATE meetings SET name = ? WHERE id = ?`, row.MeetingName, id); err != nil {
+			return 0, err
+		}
+		im.resp.Summary.MeetingsUpdated++
+		im.resp.Changes = append(im.resp.Changes, &racing.ImportChange{
+			Row:        row.Row,
+			Action:     racing.ImportAction_IMPORT_ACTION_UPDATE,
+			Kind:       "meeting",
+			Id:         id,
+			ExternalId: row.MeetingExternalId,
+			Fields:     []*racing.FieldChange{{Field: "name", OldValue: name, NewValue: row.MeetingName}},
+		})
+	default:
+		im.resp.Summary.MeetingsUnchanged++
+	}
+
+	return id, nil
+}
+
+// race creates or updates the race in row, in the meeting with meetingID.
+func (im *importer) race(ctx context.Context, row *racing.ImportRaceRow, meetingID int64) error {
+	want := &importedRace{
+		meetingID: meetingID,
+		name:      row.Name,
+		number:    row.Number,
+		visible:   row.Visible,
+		start:     row.AdvertisedStartTime.AsTime(),
+	}
+
+	var (
+		id                 int64
+		current            importedRace
+		abandoned, delayed bool
+	)
+	err := im.tx.QueryRowContext(ctx, getRaceQueries()[importRace], im.provider, row.ExternalId).
+		Scan(&id, &current.meetingID, &current.name, &current.number, &current.visible, &current.start, &abandoned, &delayed)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result, err := im.tx.ExecContext(ctx,
+			`INSERT INTO races(meeting_id, name, number, visible, advertised_start_time, provider, external_id) VALUES (?,?,?,?,?,?,?)`,
+			want.meetingID, want.name, want.number, want.visible, want.start.UTC().Format(time.RFC3339), im.provider, row.ExternalId,
+		)
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		im.resp.Summary.RacesCreated++
+		im.resp.Changes = append(im.resp.Changes, &racing.ImportChange{
+			Row:        row.Row,
+			Action:     racing.ImportAction_IMPORT_ACTION_CREATE,
+			Kind:       "race",
+			Id:         id,
+			ExternalId: row.ExternalId,
+			Fields:     want.changesFrom(nil),
+		})
+		return nil
+	case err != nil:
+		return err
+	}
+
+	moved := want.startText() != current.startText()
+	if moved && (abandoned || delayed) {
+		state := "delayed"
+		if abandoned {
+			state = "abandoned"
+		}
+		im.resp.Warnings = append(im.resp.Warnings, &racing.ImportRowError{
+			Row:         row.Row,
+			Field:       "advertised_start_time",
+			Description: fmt.Sprintf("race %q is %s, so it keeps its start time %s", row.ExternalId, state, current.startText()),
+		})
+		want.start, moved = current.start, false
+	}
+
+	fields := want.changesFrom(&current)
+	if len(fields) == 0 {
+		im.resp.Summary.RacesUnchanged++
+		return nil
+	}
+
+	if _, err := im.tx.ExecContext(ctx,
+		`UPDATE races SET meeting_id = ?, name = ?, number = ?, visible = ? WHERE id = ?`,
+		want.meetingID, want.name, want.number, want.visible, id,
+	); err != nil {
+		return err
+	}
+	if moved {
+		if err := applyScheduleChange(ctx, im.tx, id, racing.ScheduleChangeType_SCHEDULE_CHANGE_IMPORT, &want.start, "imported from "+im.provider); err != nil {
+			return err
+		}
+	}
+	im.resp.Summary.RacesUpdated++
+	im.resp.Changes = append(im.resp.Changes, &racing.ImportChange{
+		Row:        row.Row,
+		Action:     racing.ImportAction_IMPORT_ACTION_UPDATE,
+		Kind:       "race",
+		Id:         id,
+		ExternalId: row.ExternalId,
+		Fields:     fields,
+	})
+	return nil
+}
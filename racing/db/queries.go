@@ -4,20 +4,111 @@ package db
 //"strings"
 
 const (
-	racesList = "list"
+	racesList            = "list"
+	racesGet             = "get"
+	racesGetMany         = "getMany"
+	racesFacets          = "facets"
+	racesScheduleHistory = "scheduleHistory"
+	racesSearch          = "search"
+	importMeeting        = "importMeeting"
+	importRace           = "importRace"
+	importNextMeetingID  = "importNextMeetingID"
 )
 
 func getRaceQueries() map[string]string {
 	return map[string]string{
+		// The list and get queries select only the columns the read needs; the
+		// first %s is replaced by them.
 		racesList: `
-			SELECT 
-				id, 
-				meeting_id, 
-				name, 
-				number, 
-				visible, 
-				advertised_start_time 
+			SELECT %s
 			FROM races
 		`,
+		racesGet: `
+			SELECT %s
+			FROM races
+			WHERE id = ?
+		`,
+		racesGetMany: `
+			SELECT %s
+			FROM races
+			WHERE id IN (%s)
+		`,
+		// The status is worked out as deriveStatus does, from the statuses and
+		// start time passed as arguments. %s is replaced by the filter.
+		racesFacets: `
+			SELECT
+				meeting_id,
+				CASE
+					WHEN abandoned THEN ?
+					WHEN julianday(advertised_start_time) < julianday(?) THEN ?
+					WHEN delayed THEN ?
+					ELSE ?
+				END AS status,
+				visible,
+				COUNT(*)
+			FROM races
+			%s
+			GROUP BY meeting_id, status, visible
+		`,
+		racesScheduleHistory: `
+			SELECT
+				id,
+				race_id,
+				change_type,
+				previous_start_time,
+				new_start_time,
+				reason,
+				changed_at
+			FROM race_schedule_history
+			WHERE race_id = ?
+			ORDER BY id ASC
+		`,
+		// bm25() scores better matches lower and weights race name matches
+		// double; the limit is -1 for no limit.
+		racesSearch: `
+			SELECT
+				races.id,
+				races.meeting_id,
+				races.name,
+				races.number,
+				races.visible,
+				races.advertised_start_time,
+				races.abandoned,
+				races.delayed,
+				snippet(races_fts, -1, '<b>', '</b>', '…', 12),
+				bm25(races_fts, 2.0, 1.0) AS rank
+			FROM races_fts
+			JOIN races ON races.id = races_fts.rowid
+			WHERE races_fts MATCH ? AND races.visible = 1
+			ORDER BY rank ASC, races.advertised_start_time ASC
+			LIMIT ?
+		`,
+		importMeeting: `
+			SELECT id, name
+			FROM meetings
+			WHERE provider = ? AND external_id = ?
+		`,
+		importRace: `
+			SELECT
+				id,
+				meeting_id,
+				name,
+				number,
+				visible,
+				advertised_start_time,
+				abandoned,
+				delayed
+			FROM races
+			WHERE provider = ? AND external_id = ?
+		`,
+		// Races refer to meetings by ID whether or not the meeting was
+		// imported, so new meetings are numbered after both.
+		importNextMeetingID: `
+			SELECT COALESCE(MAX(id), 0) + 1 FROM (
+				SELECT MAX(id) AS id FROM meetings
+				UNION ALL
+				SELECT MAX(meeting_id) FROM races
+			)
+		`,
 	}
 }
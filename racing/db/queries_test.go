@@ -2,15 +2,25 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	//"fmt"
 	"testing"
 	"time"
 
-	"git.neds.sh/matty/entain/racing/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
 	//tspb "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // setupTestDB creates an in-memory SQLite database for testing.
@@ -19,6 +29,9 @@ func setupTestDB(t *testing.T) *sql.DB {
 	if err != nil {
 		t.Fatalf("failed to open sqlite memory db: %v", err)
 	}
+	// Every new connection to :memory: gets its own empty database, so keep
+	// the pool to a single connection.
+	db.SetMaxOpenConns(1)
 	return db
 }
 
@@ -32,7 +45,9 @@ func seedTestData(t *testing.T, db *sql.DB) {
 			name TEXT,
 			number INTEGER,
 			visible INTEGER,
-			advertised_start_time DATETIME
+			advertised_start_time DATETIME,
+			abandoned INTEGER NOT NULL DEFAULT 0,
+			delayed INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	if err != nil {
@@ -81,7 +96,9 @@ func TestListRaces_VisibleOnly(t *testing.T) {
 			name TEXT,
 			number INTEGER,
 			visible INTEGER,
-			advertised_start_time DATETIME
+			advertised_start_time DATETIME,
+			abandoned INTEGER NOT NULL DEFAULT 0,
+			delayed INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	assert.NoError(t, err, "failed to create races table")
@@ -116,8 +133,8 @@ func TestListRaces_OrderBy(t *testing.T) {
 	seedTestData(t, sqldb)
 	repo := NewRacesRepo(sqldb)
 
-	filter := &racing.ListRacesRequestFilter{}
-	races, err := repo.List(filter, "advertised_start_time", "asc")
+	filter := &racing.ListRacesRequestFilter{OnlyVisible: true}
+	races, err := repo.List(context.Background(), filter, []*racing.Sort{{Field: "advertised_start_time", Direction: "asc"}}, nil, Page{})
 	assert.NoError(t, err)
 	expected := []int64{202, 201, 203}
 	var actual []int64
@@ -126,7 +143,7 @@ func TestListRaces_OrderBy(t *testing.T) {
 	}
 	assert.Equal(t, expected, actual)
 
-	races, err = repo.List(filter, "name", "asc")
+	races, err = repo.List(context.Background(), filter, []*racing.Sort{{Field: "name", Direction: "asc"}}, nil, Page{})
 	assert.NoError(t, err)
 	expected = []int64{201, 203, 202}
 	actual = nil
@@ -147,7 +164,9 @@ func TestListRaces_Status(t *testing.T) {
 			name TEXT,
 			number INTEGER,
 			visible INTEGER,
-			advertised_start_time DATETIME
+			advertised_start_time DATETIME,
+			abandoned INTEGER NOT NULL DEFAULT 0,
+			delayed INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	assert.NoError(t, err, "failed to create races table")
@@ -171,7 +190,7 @@ func TestListRaces_Status(t *testing.T) {
 	assert.NoError(t, err, "failed to insert future race")
 
 	repo := NewRacesRepo(sqldb)
-	races, err := repo.List(nil, "", "")
+	races, err := repo.List(context.Background(), nil, nil, nil, Page{})
 	assert.NoError(t, err, "List(nil) should not error")
 
 	var foundPast, foundFuture bool
@@ -202,13 +221,15 @@ func TestGetByID(t *testing.T) {
 			name TEXT,
 			number INTEGER,
 			visible INTEGER,
-			advertised_start_time DATETIME
+			advertised_start_time DATETIME,
+			abandoned INTEGER NOT NULL DEFAULT 0,
+			delayed INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	assert.NoError(t, err)
 
 	// Insert one test race
-	now := time.Now().Format(time.RFC3339)
+	now := time.Now().Add(time.Hour).Format(time.RFC3339)
 	_, err = sqldb.Exec(`
 		INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time)
 		VALUES (?, ?, ?, ?, ?, ?)
@@ -217,15 +238,809 @@ func TestGetByID(t *testing.T) {
 
 	// Create repo and fetch the race
 	repo := NewRacesRepo(sqldb)
-	race, err := repo.GetByID(500)
+	race, err := repo.GetByID(context.Background(), 500, nil)
 	assert.NoError(t, err, "GetByID should not return an error")
 	assert.NotNil(t, race, "race should not be nil")
 	assert.Equal(t, int64(500), race.Id)
 	assert.Equal(t, "Solo Race", race.Name)
 	assert.Equal(t, int64(2), race.MeetingId)
-	assert.Equal(t, int32(5), race.Number)
+	assert.Equal(t, int64(5), race.Number)
 	assert.Equal(t, true, race.Visible)
 
 	// Check derived status (should be OPEN)
 	assert.Equal(t, racing.RaceStatus_OPEN, race.Status)
 }
+
+func TestScheduleChanges(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate(), "migrate should not error")
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_, err := sqldb.Exec(`UPDATE races SET advertised_start_time = ? WHERE id = ?`, start.Format(time.RFC3339), 201)
+	assert.NoError(t, err)
+
+	// A delay must move the race later.
+	_, err = repo.Delay(context.Background(), 201, start.Add(-time.Minute), "typo")
+	assert.ErrorIs(t, err, ErrDelayNotLater)
+
+	delayedStart := start.Add(15 * time.Minute)
+	race, err := repo.Delay(context.Background(), 201, delayedStart, "track inspection")
+	assert.NoError(t, err)
+	assert.Equal(t, racing.RaceStatus_DELAYED, race.Status)
+	assert.True(t, delayedStart.Equal(race.AdvertisedStartTime.AsTime()))
+
+	race, err = repo.Abandon(context.Background(), 201, "heavy rain")
+	assert.NoError(t, err)
+	assert.Equal(t, racing.RaceStatus_ABANDONED, race.Status)
+
+	_, err = repo.Delay(context.Background(), 201, delayedStart.Add(time.Hour), "still raining")
+	assert.ErrorIs(t, err, ErrRaceAbandoned)
+	_, err = repo.Abandon(context.Background(), 201, "again")
+	assert.ErrorIs(t, err, ErrRaceAbandoned)
+
+	race, err = repo.Reinstate(context.Background(), 201, nil, "rain cleared")
+	assert.NoError(t, err)
+	assert.Equal(t, racing.RaceStatus_DELAYED, race.Status)
+
+	_, err = repo.Reinstate(context.Background(), 201, nil, "not abandoned")
+	assert.ErrorIs(t, err, ErrRaceNotAbandoned)
+
+	_, err = repo.Abandon(context.Background(), 999, "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	history, err := repo.ScheduleHistory(context.Background(), 201)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, racing.ScheduleChangeType_SCHEDULE_CHANGE_DELAY, history[0].Type)
+		assert.True(t, start.Equal(history[0].PreviousStartTime.AsTime()))
+		assert.True(t, delayedStart.Equal(history[0].NewStartTime.AsTime()))
+		assert.Equal(t, "track inspection", history[0].Reason)
+		assert.Equal(t, racing.ScheduleChangeType_SCHEDULE_CHANGE_ABANDON, history[1].Type)
+		assert.Equal(t, racing.ScheduleChangeType_SCHEDULE_CHANGE_REINSTATE, history[2].Type)
+	}
+}
+
+func TestMigrate_AddsScheduleColumns(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+
+	// A races table as created by builds before schedule changes existed.
+	_, err := sqldb.Exec(`CREATE TABLE races (id INTEGER PRIMARY KEY, meeting_id INTEGER, name TEXT, number INTEGER, visible INTEGER, advertised_start_time DATETIME)`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`INSERT INTO races VALUES (1, 1, 'Old Race', 1, 1, ?)`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	assert.NoError(t, err)
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate())
+	assert.NoError(t, repo.migrate(), "migrate should be idempotent")
+
+	race, err := repo.GetByID(context.Background(), 1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, racing.RaceStatus_OPEN, race.Status)
+}
+
+func TestInit_WithoutDummyData(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+
+	repo := NewRacesRepo(sqldb, WithoutDummyData())
+	assert.NoError(t, repo.Init())
+
+	races, err := repo.List(context.Background(), nil, nil, nil, Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, races)
+}
+
+func TestListRaces_Page(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+	repo := NewRacesRepo(sqldb)
+
+	ids := func(sorts []*racing.Sort, page Page) []int64 {
+		races, err := repo.List(context.Background(), nil, sorts, RaceFields{"id": true}, page)
+		assert.NoError(t, err)
+		var got []int64
+		for _, race := range races {
+			got = append(got, race.Id)
+		}
+		return got
+	}
+
+	byName := []*racing.Sort{{Field: "name"}}
+	assert.Equal(t, []int64{201, 203, 202, 204}, ids(byName, Page{}))
+	assert.Equal(t, []int64{201, 203}, ids(byName, Page{Limit: 2}))
+
+	// A race sorted before the page, added after it was read, does not shift
+	// the next page.
+	_, err := sqldb.Exec(`INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time) VALUES (205, 1, 'Aardvark', 5, 1, '2025-01-01T12:00:00Z')`)
+	assert.NoError(t, err)
+	bravo := &racing.Race{Id: 203, Name: "Bravo"}
+	assert.Equal(t, []int64{202, 204}, ids(byName, Page{After: bravo, Limit: 2}))
+	assert.Equal(t, []int64{202, 204}, ids(byName, Page{After: bravo}), "a page without a limit runs to the end")
+	assert.Empty(t, ids(byName, Page{After: &racing.Race{Id: 204, Name: "Delta"}, Limit: 2}))
+
+	// Ties on a descending sort go by id, and start times stored with another
+	// UTC offset sort by when they start.
+	_, err = sqldb.Exec(`UPDATE races SET advertised_start_time = '2025-01-01T20:30:00+10:00' WHERE id = 205`)
+	assert.NoError(t, err)
+	byStart := []*racing.Sort{{Field: "advertised_start_time", Direction: "desc"}}
+	assert.Equal(t, []int64{203, 205, 201, 204, 202}, ids(byStart, Page{}))
+	after := &racing.Race{Id: 205, AdvertisedStartTime: timestamppb.New(time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC))}
+	assert.Equal(t, []int64{201, 204}, ids(byStart, Page{After: after, Limit: 2}))
+	after = &racing.Race{Id: 201, AdvertisedStartTime: timestamppb.New(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))}
+	assert.Equal(t, []int64{204, 202}, ids(byStart, Page{After: after}))
+
+	// Slice cuts the same pages from a whole list.
+	races := []*racing.Race{{Id: 3, Name: "Alpha"}, {Id: 1, Name: "Bravo"}, {Id: 2, Name: "Bravo"}}
+	slice := func(page Page) []*racing.Race {
+		got, err := page.Slice(races, byName)
+		assert.NoError(t, err)
+		return got
+	}
+	assert.Equal(t, races, slice(Page{}))
+	assert.Equal(t, races[1:2], slice(Page{After: races[0], Limit: 1}))
+	assert.Equal(t, races[2:], slice(Page{After: races[1], Limit: 5}))
+	assert.Equal(t, races[1:], slice(Page{After: &racing.Race{Id: 4, Name: "Alpha"}}), "the race a page starts after need not still be listed")
+	assert.Empty(t, slice(Page{After: races[2]}))
+
+	_, err = Page{After: races[0]}.Slice(races, []*racing.Sort{{Field: "visible"}})
+	assert.ErrorIs(t, err, ErrInvalidSortField)
+
+	assert.Equal(t, RaceFields{"id": true, "advertised_start_time": true}, PageKey(nil))
+	assert.Equal(t, RaceFields{"id": true, "meeting_id": true, "name": true}, PageKey([]*racing.Sort{{Field: "Meeting_ID"}, {Field: "name"}}))
+}
+
+func TestListRaces_InvalidSort(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+	repo := NewRacesRepo(sqldb)
+
+	_, err := repo.List(context.Background(), nil, []*racing.Sort{{Field: "meedting_id", Direction: "asc"}}, nil, Page{})
+	assert.ErrorIs(t, err, ErrInvalidSortField)
+
+	_, err = repo.List(context.Background(), nil, []*racing.Sort{{Field: "name", Direction: "sideways"}}, nil, Page{})
+	assert.ErrorIs(t, err, ErrInvalidSortDirection)
+
+	// Fields and directions ignore case, as the request validation does.
+	races, err := repo.List(context.Background(), nil, []*racing.Sort{{Field: "NAME", Direction: "Desc"}}, nil, Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, races, 4) {
+		assert.Equal(t, "Delta", races[0].Name)
+	}
+
+	// An empty sort falls back to advertised_start_time ascending.
+	races, err = repo.List(context.Background(), &racing.ListRacesRequestFilter{OnlyVisible: true}, nil, nil, Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, races, 3) {
+		assert.Equal(t, int64(202), races[0].Id)
+	}
+}
+
+func TestListRaces_MultiFieldSort(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	// A second meeting, with two races sharing a number to exercise the id
+	// tie-breaker.
+	_, err := sqldb.Exec(`
+		INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time)
+		VALUES (206, 2, 'Foxtrot', 1, 1, '2025-01-01T08:00:00Z'),
+		       (205, 2, 'Echo', 1, 1, '2025-01-01T08:30:00Z')
+	`)
+	assert.NoError(t, err)
+
+	repo := NewRacesRepo(sqldb)
+	races, err := repo.List(context.Background(), &racing.ListRacesRequestFilter{OnlyVisible: true}, []*racing.Sort{
+		{Field: "meeting_id", Direction: "desc"},
+		{Field: "number"},
+	}, nil, Page{})
+	assert.NoError(t, err)
+
+	var actual []int64
+	for _, r := range races {
+		actual = append(actual, r.Id)
+	}
+	assert.Equal(t, []int64{205, 206, 201, 202, 203}, actual)
+}
+
+func TestSearch(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate())
+
+	if !fts.Enabled {
+		_, err := repo.Search(context.Background(), "bravo", 10)
+		assert.ErrorIs(t, err, fts.ErrUnavailable)
+		return
+	}
+
+	// Races seeded before the index existed are picked up by the rebuild, and
+	// the last word is matched as a prefix.
+	results, err := repo.Search(context.Background(), "brav", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, int64(203), results[0].Race.Id)
+		assert.Equal(t, "<b>Bravo</b>", results[0].Snippet)
+		assert.Greater(t, results[0].Score, 0.0)
+	}
+
+	// Hidden races are never returned.
+	results, err = repo.Search(context.Background(), "delta", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	// The index follows inserts and renames.
+	_, err = sqldb.Exec(`INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time) VALUES (207, 1, 'Bravo Stakes', 5, 1, '2025-01-01T12:00:00Z')`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`UPDATE races SET name = 'Alpha Plate' WHERE id = 201`)
+	assert.NoError(t, err)
+
+	results, err = repo.Search(context.Background(), "bravo", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, int64(203), results[0].Race.Id, "the closer match should rank higher")
+		assert.Equal(t, int64(207), results[1].Race.Id)
+	}
+
+	results, err = repo.Search(context.Background(), "plate", 1)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, int64(201), results[0].Race.Id)
+	}
+
+	results, err = repo.Search(context.Background(), `"*`, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results, "input without terms should match nothing")
+}
+
+func TestSearch_Meetings(t *testing.T) {
+	if !fts.Enabled {
+		t.Skip("needs -tags sqlite_fts5")
+	}
+
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	repo := &racesRepo{db: sqldb}
+	_, err := sqldb.Exec(`CREATE TABLE meetings (id INTEGER PRIMARY KEY, provider TEXT NOT NULL, external_id TEXT NOT NULL, name TEXT NOT NULL)`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`INSERT INTO meetings(id, provider, external_id, name) VALUES (1, 'tab', 'M1', 'Flemington')`)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.migrate())
+
+	ids := func(query string) []int64 {
+		results, err := repo.Search(context.Background(), query, 10)
+		assert.NoError(t, err)
+		var ids []int64
+		for _, r := range results {
+			ids = append(ids, r.Race.Id)
+		}
+		return ids
+	}
+
+	// Existing races are indexed under their meeting, soonest first as the
+	// matches are equal; hidden race 204 is not returned.
+	assert.Equal(t, []int64{202, 201, 203}, ids("flemington"))
+
+	// A race name match counts for more than a venue match.
+	_, err = sqldb.Exec(`INSERT INTO meetings(id, provider, external_id, name) VALUES (2, 'tab', 'M2', 'Alpha Park')`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time) VALUES (207, 2, 'Maiden', 1, 1, '2020-01-01T12:00:00Z')`)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{201, 207}, ids("alpha"))
+
+	// The index follows meeting renames and races moving meeting.
+	_, err = sqldb.Exec(`UPDATE meetings SET name = 'Caulfield' WHERE id = 1`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`UPDATE races SET meeting_id = 2 WHERE id = 203`)
+	assert.NoError(t, err)
+	assert.Empty(t, ids("flemington"))
+	assert.Equal(t, []int64{202, 201}, ids("caulfield"))
+	assert.Equal(t, []int64{207, 203}, ids("park"))
+}
+
+func TestMigrate_ReplacesFTS4Index(t *testing.T) {
+	if !fts.Enabled {
+		t.Skip("needs -tags sqlite_fts5")
+	}
+
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	// The index and triggers as an FTS4 build left them.
+	for _, statement := range []string{
+		`CREATE VIRTUAL TABLE races_fts USING fts4(content="races", name, tokenize=unicode61)`,
+		`CREATE TRIGGER races_fts_before_update BEFORE UPDATE OF name ON races BEGIN DELETE FROM races_fts WHERE docid = old.id; END`,
+		`CREATE TRIGGER races_fts_after_insert AFTER INSERT ON races BEGIN INSERT INTO races_fts(docid, name) VALUES (new.id, new.name); END`,
+	} {
+		_, err := sqldb.Exec(statement)
+		assert.NoError(t, err)
+	}
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate())
+	assert.NoError(t, repo.migrate(), "migrating twice is harmless")
+
+	_, err := sqldb.Exec(`UPDATE races SET name = 'Bravo Plate' WHERE id = 201`)
+	assert.NoError(t, err)
+
+	results, err := repo.Search(context.Background(), "bravo", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestQueryObserver(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	var observed []string
+	repo := NewRacesRepo(sqldb, WithQueryObserver(func(query string, d time.Duration) {
+		assert.True(t, d > 0, "duration of %s", query)
+		observed = append(observed, query)
+	}))
+
+	_, err := repo.List(context.Background(), nil, nil, nil, Page{})
+	assert.NoError(t, err)
+	_, err = repo.GetByID(context.Background(), 201, nil)
+	assert.NoError(t, err)
+	_, err = repo.GetByID(context.Background(), 999, nil)
+	assert.Error(t, err, "a missing race is still observed")
+
+	assert.Equal(t, []string{"list", "get", "get"}, observed)
+}
+
+func TestContextErrors(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewRacesRepo(sqldb).List(cancelled, nil, nil, nil, Page{})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = NewRacesRepo(sqldb).Delay(cancelled, 201, time.Now().Add(time.Hour), "weather")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	repo := NewRacesRepo(sqldb, WithQueryTimeout(time.Nanosecond))
+	_, err = repo.GetByID(context.Background(), 201, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	if fts.Enabled {
+		_, err = repo.Search(context.Background(), "race", 10)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	race, err := NewRacesRepo(sqldb, WithQueryTimeout(time.Minute)).GetByID(context.Background(), 201, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(201), race.Id)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+	repo := NewRacesRepo(sqldb)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "ListRaces")
+	_, err := repo.List(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{1}}, nil, nil, Page{})
+	assert.NoError(t, err)
+	_, err = repo.GetByID(ctx, 999, nil)
+	assert.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+
+	list, get := spans[0], spans[1]
+	assert.Equal(t, "racesRepo.List", list.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), list.Parent().SpanID())
+	var query string
+	for _, kv := range list.Attributes() {
+		if kv.Key == semconv.DBQueryTextKey {
+			query = kv.Value.AsString()
+		}
+	}
+	// The generated SQL is recorded, but not its arguments.
+	assert.Contains(t, query, "WHERE meeting_id IN (?)")
+
+	assert.Equal(t, "racesRepo.GetByID", get.Name())
+	assert.Equal(t, codes.Error, get.Status().Code)
+}
+
+func TestGetByIDs(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+	repo := NewRacesRepo(sqldb)
+
+	races, err := repo.GetByIDs(context.Background(), []int64{203, 999, 201}, RaceFields{"id": true, "status": true})
+	assert.NoError(t, err)
+	got := map[int64]racing.RaceStatus{}
+	for _, race := range races {
+		got[race.Id] = race.Status
+		assert.Empty(t, race.Name, "only the fields asked for")
+	}
+	assert.Equal(t, map[int64]racing.RaceStatus{201: racing.RaceStatus_CLOSED, 203: racing.RaceStatus_CLOSED}, got, "missing races are left out")
+
+	races, err = repo.GetByIDs(context.Background(), nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, races)
+}
+
+func TestImport(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate())
+	assert.NoError(t, repo.migrate(), "migrate should be idempotent")
+	ctx := context.Background()
+
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	row := func(n int64, meeting, meetingName, race, name string, visible bool, start time.Time) *racing.ImportRaceRow {
+		return &racing.ImportRaceRow{
+			Row:                 n,
+			MeetingExternalId:   meeting,
+			MeetingName:         meetingName,
+			ExternalId:          race,
+			Name:                name,
+			Number:              n,
+			Visible:             visible,
+			AdvertisedStartTime: timestamppb.New(start),
+		}
+	}
+	rows := []*racing.ImportRaceRow{
+		row(1, "M1", "Flemington", "R1", "Melbourne Cup", true, start),
+		row(2, "M1", "Flemington", "R2", "Oaks", false, start.Add(time.Hour)),
+		row(3, "M2", "Randwick", "R3", "Golden Slipper", true, start),
+	}
+
+	type change struct {
+		action racing.ImportAction
+		kind   string
+		id     int64
+		fields []string
+	}
+	summarise := func(changes []*racing.ImportChange) []change {
+		var got []change
+		for _, c := range changes {
+			var fields []string
+			for _, f := range c.Fields {
+				fields = append(fields, f.Field)
+			}
+			got = append(got, change{c.Action, c.Kind, c.Id, fields})
+		}
+		return got
+	}
+	raceFields := []string{"meeting_id", "name", "number", "visible", "advertised_start_time"}
+	// New meetings are numbered after the meetings races already refer to,
+	// and new races after the existing ones.
+	created := []change{
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "meeting", 2, []string{"name"}},
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "race", 205, raceFields},
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "race", 206, raceFields},
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "meeting", 3, []string{"name"}},
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "race", 207, raceFields},
+	}
+
+	// A dry run reports the changes but writes nothing.
+	resp, err := repo.Import(ctx, "tab", rows, true)
+	assert.NoError(t, err)
+	assert.Equal(t, created, summarise(resp.Changes))
+	assert.True(t, proto.Equal(&racing.ImportSummary{Rows: 3, MeetingsCreated: 2, RacesCreated: 3}, resp.Summary), resp.Summary)
+	races, err := repo.GetByIDs(ctx, []int64{205, 206, 207}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, races, "a dry run writes nothing")
+
+	resp, err = repo.Import(ctx, "tab", rows, false)
+	assert.NoError(t, err)
+	assert.Equal(t, created, summarise(resp.Changes))
+
+	race, err := repo.GetByID(ctx, 206, nil)
+	assert.NoError(t, err)
+	assertRace(t, &racing.Race{
+		Id:                  206,
+		MeetingId:           2,
+		Name:                "Oaks",
+		Number:              2,
+		AdvertisedStartTime: timestamppb.New(start.Add(time.Hour)),
+		Status:              racing.RaceStatus_OPEN,
+	}, race, "imported race")
+	if fts.Enabled {
+		results, err := repo.Search(ctx, "slipper", 10)
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1, "imported races are searchable") {
+			assert.Equal(t, int64(207), results[0].Race.Id)
+		}
+	}
+
+	// Importing again matches on the provider's IDs: only what differs is
+	// updated.
+	rows[0] = row(1, "M1", "Flemington Park", "R1", "Melbourne Cup", true, start.Add(30*time.Minute))
+	resp, err = repo.Import(ctx, "tab", rows, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []change{
+		{racing.ImportAction_IMPORT_ACTION_UPDATE, "meeting", 2, []string{"name"}},
+		{racing.ImportAction_IMPORT_ACTION_UPDATE, "race", 205, []string{"advertised_start_time"}},
+	}, summarise(resp.Changes))
+	assert.Equal(t, "2030-01-01T10:00:00Z", resp.Changes[1].Fields[0].OldValue)
+	assert.Equal(t, "2030-01-01T10:30:00Z", resp.Changes[1].Fields[0].NewValue)
+	assert.True(t, proto.Equal(&racing.ImportSummary{Rows: 3, MeetingsUpdated: 1, MeetingsUnchanged: 1, RacesUpdated: 1, RacesUnchanged: 2}, resp.Summary), resp.Summary)
+	assert.Empty(t, resp.Warnings)
+
+	// The new start time is a schedule change, recorded in the race's history.
+	history, err := repo.ScheduleHistory(ctx, 205)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, racing.ScheduleChangeType_SCHEDULE_CHANGE_IMPORT, history[0].Type)
+		assert.Equal(t, start, history[0].PreviousStartTime.AsTime())
+		assert.Equal(t, start.Add(30*time.Minute), history[0].NewStartTime.AsTime())
+		assert.Equal(t, "imported from tab", history[0].Reason)
+	}
+
+	// The same IDs from another provider are other meetings and races.
+	resp, err = repo.Import(ctx, "ubet", rows[:1], false)
+	assert.NoError(t, err)
+	assert.Equal(t, []change{
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "meeting", 4, []string{"name"}},
+		{racing.ImportAction_IMPORT_ACTION_CREATE, "race", 208, raceFields},
+	}, summarise(resp.Changes))
+}
+
+func TestImport_TraderChanges(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	repo := &racesRepo{db: sqldb}
+	assert.NoError(t, repo.migrate())
+	ctx := context.Background()
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	row := func(n int64, race, name string, start time.Time) *racing.ImportRaceRow {
+		return &racing.ImportRaceRow{
+			Row:                 n,
+			MeetingExternalId:   "M1",
+			MeetingName:         "Flemington",
+			ExternalId:          race,
+			Name:                name,
+			Number:              n,
+			Visible:             true,
+			AdvertisedStartTime: timestamppb.New(start),
+		}
+	}
+	_, err := repo.Import(ctx, "tab", []*racing.ImportRaceRow{
+		row(1, "R1", "Melbourne Cup", start),
+		row(2, "R2", "Oaks", start),
+	}, false)
+	assert.NoError(t, err)
+
+	// A trader delays one race and abandons the other.
+	_, err = repo.Delay(ctx, 205, start.Add(time.Hour), "track inspection")
+	assert.NoError(t, err)
+	_, err = repo.Abandon(ctx, 206, "heavy rain")
+	assert.NoError(t, err)
+
+	// The feed still has the old start times, and a new one for R2. Neither
+	// overrides the trader, but the rest of each row is still imported.
+	resp, err := repo.Import(ctx, "tab", []*racing.ImportRaceRow{
+		row(1, "R1", "Melbourne Cup (G1)", start),
+		row(2, "R2", "Oaks", start.Add(2*time.Hour)),
+	}, false)
+	assert.NoError(t, err)
+
+	type warning struct {
+		row   int64
+		field string
+	}
+	var warnings []warning
+	for _, w := range resp.Warnings {
+		warnings = append(warnings, warning{w.Row, w.Field})
+	}
+	assert.Equal(t, []warning{{1, "advertised_start_time"}, {2, "advertised_start_time"}}, warnings)
+	if assert.Len(t, resp.Changes, 1) {
+		assert.Equal(t, int64(205), resp.Changes[0].Id)
+		assert.Len(t, resp.Changes[0].Fields, 1)
+		assert.Equal(t, "name", resp.Changes[0].Fields[0].Field)
+	}
+	assert.True(t, proto.Equal(&racing.ImportSummary{Rows: 2, MeetingsUnchanged: 1, RacesUpdated: 1, RacesUnchanged: 1}, resp.Summary), resp.Summary)
+
+	race, err := repo.GetByID(ctx, 205, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Melbourne Cup (G1)", race.Name)
+	assert.Equal(t, start.Add(time.Hour), race.AdvertisedStartTime.AsTime(), "the delay stands")
+	assert.Equal(t, racing.RaceStatus_DELAYED, race.Status)
+
+	race, err = repo.GetByID(ctx, 206, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, start, race.AdvertisedStartTime.AsTime())
+	assert.Equal(t, racing.RaceStatus_ABANDONED, race.Status)
+
+	for _, id := range []int64{205, 206} {
+		history, err := repo.ScheduleHistory(ctx, id)
+		assert.NoError(t, err)
+		assert.Len(t, history, 1, "race %d has only the trader's change", id)
+	}
+}
+
+func TestFacets(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+
+	// Add races in every status, with a start time in another time zone.
+	future := time.Now().Add(time.Hour)
+	for _, race := range []struct {
+		id, meetingID      int64
+		visible            bool
+		start              string
+		abandoned, delayed bool
+	}{
+		{id: 205, meetingID: 2, visible: true, start: future.UTC().Format(time.RFC3339)},
+		{id: 206, meetingID: 2, visible: false, start: future.In(time.FixedZone("AEST", 10*60*60)).Format(time.RFC3339), delayed: true},
+		{id: 207, meetingID: 3, visible: true, start: future.UTC().Format(time.RFC3339), abandoned: true},
+		{id: 208, meetingID: 3, visible: true, start: time.Now().Add(-time.Minute).In(time.FixedZone("AEST", 10*60*60)).Format(time.RFC3339), delayed: true},
+	} {
+		_, err := sqldb.Exec(`INSERT INTO races(id, meeting_id, name, number, visible, advertised_start_time, abandoned, delayed) VALUES (?,?,?,?,?,?,?,?)`,
+			race.id, race.meetingID, "Race", 1, race.visible, race.start, race.abandoned, race.delayed)
+		assert.NoError(t, err)
+	}
+	repo := NewRacesRepo(sqldb)
+
+	facets, err := repo.Facets(context.Background(), nil)
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 8, facets.TotalCount)
+		assert.EqualValues(t, 6, facets.VisibleCount)
+		assert.EqualValues(t, 2, facets.HiddenCount)
+		assert.Equal(t, map[int64]int64{1: 4, 2: 2, 3: 2}, meetingCounts(facets))
+		assert.Equal(t, map[racing.RaceStatus]int64{
+			racing.RaceStatus_OPEN:      1,
+			racing.RaceStatus_CLOSED:    5,
+			racing.RaceStatus_ABANDONED: 1,
+			racing.RaceStatus_DELAYED:   1,
+		}, statusCounts(facets))
+	}
+
+	// Facets follow every filter, and agree with the races listed.
+	filters := []*racing.ListRacesRequestFilter{
+		{OnlyVisible: true},
+		{MeetingIds: []int64{2, 3}},
+		{MeetingIds: []int64{2, 3}, OnlyVisible: true},
+		{MeetingIds: []int64{9}},
+	}
+	for _, filter := range filters {
+		races, err := repo.List(context.Background(), filter, nil, nil, Page{})
+		assert.NoError(t, err)
+		facets, err := repo.Facets(context.Background(), filter)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		wantMeetings, wantStatuses := map[int64]int64{}, map[racing.RaceStatus]int64{}
+		var visible int64
+		for _, race := range races {
+			wantMeetings[race.MeetingId]++
+			wantStatuses[race.Status]++
+			if race.Visible {
+				visible++
+			}
+		}
+		assert.EqualValues(t, len(races), facets.TotalCount, "%v", filter)
+		assert.Equal(t, visible, facets.VisibleCount, "%v", filter)
+		assert.Equal(t, wantMeetings, meetingCounts(facets), "%v", filter)
+		assert.Equal(t, wantStatuses, statusCounts(facets), "%v", filter)
+	}
+}
+
+// meetingCounts returns the meeting facet as a map.
+func meetingCounts(facets *racing.RaceFacets) map[int64]int64 {
+	counts := map[int64]int64{}
+	for _, c := range facets.Meetings {
+		counts[c.MeetingId] = c.Count
+	}
+	return counts
+}
+
+// statusCounts returns the status facet as a map.
+func statusCounts(facets *racing.RaceFacets) map[racing.RaceStatus]int64 {
+	counts := map[racing.RaceStatus]int64{}
+	for _, c := range facets.Statuses {
+		counts[c.Status] = c.Count
+	}
+	return counts
+}
+
+func TestParseReadMask(t *testing.T) {
+	fields, err := ParseReadMask(&fieldmaskpb.FieldMask{Paths: []string{"id", "status"}})
+	assert.NoError(t, err)
+	assert.Equal(t, RaceFields{"id": true, "status": true}, fields)
+
+	for _, mask := range []*fieldmaskpb.FieldMask{nil, {}, {Paths: []string{"name", "*"}}} {
+		fields, err := ParseReadMask(mask)
+		assert.NoError(t, err)
+		assert.Nil(t, fields, "%v reads every field", mask)
+	}
+
+	_, err = ParseReadMask(&fieldmaskpb.FieldMask{Paths: []string{"name", "runners"}})
+	assert.ErrorIs(t, err, ErrUnknownField)
+	_, err = ParseReadMask(&fieldmaskpb.FieldMask{Paths: []string{"advertised_start_time.seconds"}})
+	assert.ErrorIs(t, err, ErrUnknownField)
+
+	// Every Race field can be asked for.
+	race := (&racing.Race{}).ProtoReflect().Descriptor().Fields()
+	for i := 0; i < race.Len(); i++ {
+		assert.Contains(t, raceFieldColumns, string(race.Get(i).Name()))
+	}
+}
+
+func TestReadMask(t *testing.T) {
+	sqldb := setupTestDB(t)
+	defer sqldb.Close()
+	seedTestData(t, sqldb)
+	repo := NewRacesRepo(sqldb)
+	ctx := context.Background()
+
+	races, err := repo.List(ctx, &racing.ListRacesRequestFilter{OnlyVisible: true}, nil, RaceFields{"id": true, "name": true}, Page{})
+	if assert.NoError(t, err) && assert.Len(t, races, 3) {
+		assertRace(t, &racing.Race{Id: 202, Name: "Charlie"}, races[0], "only the fields asked for")
+	}
+
+	race, err := repo.GetByID(ctx, 201, RaceFields{"status": true})
+	if assert.NoError(t, err) {
+		assertRace(t, &racing.Race{Status: racing.RaceStatus_CLOSED}, race, "status without its start time")
+	}
+
+	// Columns no field needs are not read at all, so a table without them
+	// still serves the fields that do not.
+	_, err = sqldb.Exec(`CREATE TABLE slim AS SELECT id, name FROM races`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`DROP TABLE races`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`ALTER TABLE slim RENAME TO races`)
+	assert.NoError(t, err)
+
+	_, err = repo.List(ctx, nil, []*racing.Sort{{Field: "name"}}, RaceFields{"id": true, "name": true}, Page{})
+	assert.NoError(t, err)
+	_, err = repo.GetByID(ctx, 201, RaceFields{"name": true})
+	assert.NoError(t, err)
+	_, err = repo.GetByID(ctx, 201, nil)
+	assert.Error(t, err)
+}
+
+// assertRace compares races by value, which assert.Equal cannot do for protos.
+func assertRace(t *testing.T, want, got *racing.Race, msg string) {
+	t.Helper()
+	assert.True(t, proto.Equal(want, got), "%s: got %v", msg, got)
+}
+
+func TestRaceFields_Apply(t *testing.T) {
+	race := &racing.Race{Id: 1, MeetingId: 2, Name: "Cup", Number: 3, Visible: true, Status: racing.RaceStatus_OPEN}
+
+	RaceFields(nil).Apply(race)
+	assert.Equal(t, "Cup", race.Name, "nil keeps every field")
+
+	RaceFields{"id": true, "name": true}.Apply(race)
+	assertRace(t, &racing.Race{Id: 1, Name: "Cup"}, race, "masked")
+
+	assert.Equal(t, RaceFields{"id": true, "visible": true}, RaceFields{"id": true}.With("visible"))
+	assert.Nil(t, RaceFields(nil).With("visible"))
+}
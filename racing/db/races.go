@@ -1,15 +1,21 @@
 package db
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/golang/protobuf/ptypes"
 	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
-	"git.neds.sh/matty/entain/racing/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
 )
 
 // RacesRepo provides repository access to races.
@@ -17,20 +23,100 @@ type RacesRepo interface {
 	// Init will initialise our races repository.
 	Init() error
 
-	// List will return a list of races.
-	List(filter *racing.ListRacesRequestFilter, sortField, sortDirection string) ([]*racing.Race, error)
+	// List will return a list of races ordered by each sort in turn, then by id.
+	// Only the given fields of each race are read, and only those in page.
+	List(ctx context.Context, filter *racing.ListRacesRequestFilter, sorts []*racing.Sort, fields RaceFields, page Page) ([]*racing.Race, error)
 
-	GetByID(id int64) (*racing.Race, error)
+	// GetByID returns the given fields of a race.
+	GetByID(ctx context.Context, id int64, fields RaceFields) (*racing.Race, error)
+
+	// Facets counts the races matching filter, in total and by meeting,
+	// status and visibility.
+	Facets(ctx context.Context, filter *racing.ListRacesRequestFilter) (*racing.RaceFacets, error)
+
+	// GetByIDs returns the given fields of those of the races that exist, in no
+	// particular order.
+	GetByIDs(ctx context.Context, ids []int64, fields RaceFields) ([]*racing.Race, error)
+
+	// Delay moves a race to a later start time, recording the change.
+	Delay(ctx context.Context, id int64, newStart time.Time, reason string) (*racing.Race, error)
+
+	// Abandon marks a race as abandoned, recording the change.
+	Abandon(ctx context.Context, id int64, reason string) (*racing.Race, error)
+
+	// Reinstate clears a race's abandonment, optionally moving it to newStart.
+	Reinstate(ctx context.Context, id int64, newStart *time.Time, reason string) (*racing.Race, error)
+
+	// ScheduleHistory returns every schedule change made to a race, oldest first.
+	ScheduleHistory(ctx context.Context, id int64) ([]*racing.ScheduleChange, error)
+
+	// Search returns up to limit visible races whose name or meeting name
+	// matches query, best match first.
+	Search(ctx context.Context, query string, limit int) ([]*racing.SearchResult, error)
+
+	// Import creates and updates the meetings and races in rows, matching them
+	// on provider's IDs, in a single transaction. It returns what changed,
+	// leaving Applied unset. A dry run rolls the transaction back.
+	Import(ctx context.Context, provider string, rows []*racing.ImportRaceRow, dryRun bool) (*racing.ImportRacesResponse, error)
 }
 
+var (
+	// ErrRaceAbandoned is returned when delaying or abandoning a race that is
+	// already abandoned.
+	ErrRaceAbandoned = errors.New("race is abandoned")
+	// ErrRaceDelayed is returned when an import would move the start time of
+	// a race a trader has delayed.
+	ErrRaceDelayed = errors.New("race is delayed")
+	// ErrRaceNotAbandoned is returned when reinstating a race that is not
+	// abandoned.
+	ErrRaceNotAbandoned = errors.New("race is not abandoned")
+	// ErrDelayNotLater is returned when a delay does not move the race to a
+	// later start time.
+	ErrDelayNotLater = errors.New("new start time must be after the current start time")
+	// ErrInvalidSortField is returned when listing races by an unsortable field.
+	ErrInvalidSortField = errors.New("invalid sort field")
+	// ErrInvalidSortDirection is returned when a sort direction is neither ASC
+	// nor DESC.
+	ErrInvalidSortDirection = errors.New("invalid sort direction")
+)
+
 type racesRepo struct {
-	db   *sql.DB
-	init sync.Once
+	db           *sql.DB
+	init         sync.Once
+	noSeed       bool
+	observe      func(query string, d time.Duration)
+	queryTimeout time.Duration
+}
+
+// Option configures a races repository.
+type Option func(*racesRepo)
+
+// WithoutDummyData makes Init create the schema without seeding dummy races.
+func WithoutDummyData() Option {
+	return func(r *racesRepo) { r.noSeed = true }
+}
+
+// WithQueryTimeout cancels any repository call still running after d, failing
+// it with an error wrapping context.DeadlineExceeded. Zero means no limit
+// beyond the caller's own deadline.
+func WithQueryTimeout(d time.Duration) Option {
+	return func(r *racesRepo) { r.queryTimeout = d }
+}
+
+// WithQueryObserver calls observe after each repository call with the query
+// type ("list", "get", "get_many", "facets", "search", "history", "schedule"
+// or "import") and how long it took, rows included.
+func WithQueryObserver(observe func(query string, d time.Duration)) Option {
+	return func(r *racesRepo) { r.observe = observe }
 }
 
 // NewRacesRepo creates a new races repository.
-func NewRacesRepo(db *sql.DB) RacesRepo {
-	return &racesRepo{db: db}
+func NewRacesRepo(db *sql.DB, opts ...Option) RacesRepo {
+	r := &racesRepo{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Init prepares the race repository dummy data.
@@ -40,41 +126,303 @@ func (r *racesRepo) Init() error {
 	r.init.Do(func() {
 		// For test/example purposes, we seed the DB with some dummy races.
 		err = r.seed()
+		if err == nil {
+			err = r.migrate()
+		}
 	})
 
 	return err
 }
 
-func (r *racesRepo) List(filter *racing.ListRacesRequestFilter, sortField, sortDirection string) ([]*racing.Race, error) {
+// Page is a window on a list of races: up to Limit of them, starting after
+// the race After in the list's order. The zero Page is the whole list.
+//
+// After needs only the race's PageKey fields. Paging from the last race seen,
+// rather than by offset, means races added, removed or moved between pages do
+// not make later pages skip or repeat races.
+type Page struct {
+	After *racing.Race
+	Limit int
+}
+
+// PageKey returns the fields that place a race in a list in sorts order: its
+// ID and the fields it is sorted by.
+func PageKey(sorts []*racing.Sort) RaceFields {
+	if len(sorts) == 0 {
+		sorts = defaultSorts
+	}
+	key := RaceFields{"id": true}
+	for _, sort := range sorts {
+		key[strings.ToLower(sort.GetField())] = true
+	}
+	return key
+}
+
+// Slice returns the part of races, a whole list in sorts order, that is in p.
+func (p Page) Slice(races []*racing.Race, sorts []*racing.Sort) ([]*racing.Race, error) {
+	if p.After != nil {
+		keys, err := sortKeys(sorts)
+		if err != nil {
+			return nil, err
+		}
+		races = races[sort.Search(len(races), func(i int) bool {
+			return compareRaces(keys, races[i], p.After) > 0
+		}):]
+	}
+	if p.Limit > 0 && p.Limit < len(races) {
+		races = races[:p.Limit]
+	}
+	return races, nil
+}
+
+func (r *racesRepo) List(ctx context.Context, filter *racing.ListRacesRequestFilter, sorts []*racing.Sort, fields RaceFields, page Page) (races []*racing.Race, err error) {
 	var (
-		err   error
-		query string
-		args  []interface{}
+		query   string
+		args    []interface{}
+		columns = fields.columns()
 	)
 
-	query = getRaceQueries()[racesList]
+	query = fmt.Sprintf(getRaceQueries()[racesList], selectList(columns))
 
-	query, args = r.applyFilter(query, filter, sortField, sortDirection)
+	query, args, err = r.applyFilter(query, filter, sorts, page.After)
+	if err != nil {
+		return nil, err
+	}
+	if page.Limit > 0 {
+		query, args = query+" LIMIT ?", append(args, page.Limit)
+	}
 
-	rows, err := r.db.Query(query, args...)
+	ctx, done := r.startQuery(ctx, "list", "racesRepo.List", query)
+	defer func() { done(&err) }()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		race, err := fields.scan(rows, columns)
+		if err != nil {
+			return nil, err
+		}
+		races = append(races, race)
+	}
+
+	return races, rows.Err()
+}
+
+// startQuery begins a repository call of the given type running query: it
+// bounds ctx by the query timeout and starts the call's span. Calling done with
+// the call's error ends the span, reports the duration to the query observer
+// and, if the call failed because ctx ended, makes the error say so.
+func (r *racesRepo) startQuery(ctx context.Context, kind, spanName, query string) (context.Context, func(*error)) {
+	start := time.Now()
+
+	cancel := context.CancelFunc(func() {})
+	if r.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.queryTimeout)
+	}
+	ctx, span := startSpan(ctx, spanName, query)
+
+	return ctx, func(err *error) {
+		*err = contextError(ctx, *err)
+		endSpan(span, *err)
+		cancel()
+		if r.observe != nil {
+			r.observe(kind, time.Since(start))
+		}
+	}
+}
+
+// contextError returns err wrapping ctx's error when ctx has ended. The driver
+// reports a query cut short as merely interrupted, so without this callers
+// could not tell a timeout from any other failure.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ctx.Err(), err)
+}
+
+// sortableFields are the races columns a list may be ordered by.
+var sortableFields = map[string]bool{
+	"advertised_start_time": true,
+	"meeting_id":            true,
+	"name":                  true,
+	"number":                true,
+}
+
+// IsSortableField reports whether races can be ordered by field. Like the
+// request validation, it ignores case.
+func IsSortableField(field string) bool {
+	return sortableFields[strings.ToLower(field)]
+}
+
+// defaultSorts order a list when the request gives no sorts.
+var defaultSorts = []*racing.Sort{{Field: "advertised_start_time"}}
+
+// sortKey is one term of a list's order.
+type sortKey struct {
+	field string
+	desc  bool
+}
 
-	return r.scanRaces(rows)
+// sortKeys returns the order sorts give a list, which always ends with id
+// ascending as the tie-breaker. With no sorts races are ordered by
+// advertised_start_time; unknown fields or directions are rejected rather than
+// quietly replaced.
+func sortKeys(sorts []*racing.Sort) ([]sortKey, error) {
+	if len(sorts) == 0 {
+		sorts = defaultSorts
+	}
+
+	keys := make([]sortKey, 0, len(sorts)+1)
+	for _, sort := range sorts {
+		if !IsSortableField(sort.GetField()) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSortField, sort.GetField())
+		}
+
+		key := sortKey{field: strings.ToLower(sort.GetField())}
+		switch strings.ToUpper(sort.GetDirection()) {
+		case "", "ASC":
+		case "DESC":
+			key.desc = true
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSortDirection, sort.GetDirection())
+		}
+		keys = append(keys, key)
+	}
+	return append(keys, sortKey{field: "id"}), nil
 }
 
-func (r *racesRepo) applyFilter(query string, filter *racing.ListRacesRequestFilter, sortField, sortDirection string) (string, []interface{}) {
+// expr returns the SQL expression k orders operand, a column or placeholder,
+// by. Start times are compared with julianday, so races stored with other UTC
+// offsets sort by when they start.
+func (k sortKey) expr(operand string) string {
+	if k.field == "advertised_start_time" {
+		return "julianday(" + operand + ")"
+	}
+	return operand
+}
+
+// value returns race's value for k, as an argument to k.expr("?").
+func (k sortKey) value(race *racing.Race) interface{} {
+	switch k.field {
+	case "meeting_id":
+		return race.GetMeetingId()
+	case "name":
+		return race.GetName()
+	case "number":
+		return race.GetNumber()
+	case "advertised_start_time":
+		return race.GetAdvertisedStartTime().AsTime().UTC().Format(time.RFC3339)
+	default:
+		return race.GetId()
+	}
+}
+
+// compare orders a and b by k the way the database does.
+func (k sortKey) compare(a, b *racing.Race) int {
+	var c int
+	switch k.field {
+	case "meeting_id":
+		c = cmp.Compare(a.GetMeetingId(), b.GetMeetingId())
+	case "name":
+		c = strings.Compare(a.GetName(), b.GetName())
+	case "number":
+		c = cmp.Compare(a.GetNumber(), b.GetNumber())
+	case "advertised_start_time":
+		c = a.GetAdvertisedStartTime().AsTime().Compare(b.GetAdvertisedStartTime().AsTime())
+	default:
+		c = cmp.Compare(a.GetId(), b.GetId())
+	}
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// compareRaces orders a and b by keys.
+func compareRaces(keys []sortKey, a, b *racing.Race) int {
+	for _, key := range keys {
+		if c := key.compare(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// afterClause returns the condition matching the races that come after race
+// in the order keys give, and its arguments: those greater on the first key,
+// or equal on it and greater on the next, and so on.
+func afterClause(keys []sortKey, race *racing.Race) (string, []interface{}) {
 	var (
-		clauses []string
-		args    []interface{}
+		terms []string
+		args  []interface{}
 	)
+	for i, key := range keys {
+		conds := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			conds = append(conds, prev.expr(prev.field)+" = "+prev.expr("?"))
+			args = append(args, prev.value(race))
+		}
+
+		op := " > "
+		if key.desc {
+			op = " < "
+		}
+		conds = append(conds, key.expr(key.field)+op+key.expr("?"))
+		args = append(args, key.value(race))
 
-	if filter == nil {
-		return query, args
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
 	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// applyFilter adds the WHERE and ORDER BY clauses for filter and sorts, keeping
+// only the races after after, if it is set.
+func (r *racesRepo) applyFilter(query string, filter *racing.ListRacesRequestFilter, sorts []*racing.Sort, after *racing.Race) (string, []interface{}, error) {
+	keys, err := sortKeys(sorts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where, args := filterClause(filter)
+	if after != nil {
+		cond, condArgs := afterClause(keys, after)
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+		args = append(args, condArgs...)
+	}
+	query += where
+
+	orderBy := make([]string, len(keys))
+	for i, key := range keys {
+		direction := " ASC"
+		if key.desc {
+			direction = " DESC"
+		}
+		orderBy[i] = key.expr(key.field) + direction
+	}
+
+	query += " ORDER BY " + strings.Join(orderBy, ", ")
 
-	if len(filter.MeetingIds) > 0 {
+	return query, args, nil
+}
+
+// filterClause returns the WHERE clause for filter, if it needs one, and its
+// arguments.
+func filterClause(filter *racing.ListRacesRequestFilter) (string, []interface{}) {
+	var (
+		clauses []string
+		args    []interface{}
+	)
+
+	if len(filter.GetMeetingIds()) > 0 {
 		clauses = append(clauses, "meeting_id IN ("+strings.Repeat("?,", len(filter.MeetingIds)-1)+"?)")
 
 		for _, meetingID := range filter.MeetingIds {
@@ -82,109 +430,330 @@ func (r *racesRepo) applyFilter(query string, filter *racing.ListRacesRequestFil
 		}
 	}
 
-	placeholders := strings.Repeat("?,", len(filter.MeetingIds)-1) + "?"
-	clauses = append(clauses, "meedting_id IN ("+placeholders+")")
-	for _, meetingID := range filter.MeetingIds {
-		args = append(args, meetingID)
-	}
-
-	if filter.OnlyVisible {
+	if filter.GetOnlyVisible() {
 		clauses = append(clauses, "visible = 1")
 	}
 
-	if len(clauses) != 0 {
-		query += " WHERE " + strings.Join(clauses, " AND ")
+	if len(clauses) == 0 {
+		return "", nil
 	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
 
-	// Allowed fields for sorting
-	allowedOrderFields := map[string]bool{
-		"advertised_start_time": true,
-		"name":                  true,
-		"number":                true,
+// Facets counts the races matching filter with a single GROUP BY over meeting,
+// status and visibility, and adds the groups up into each facet.
+func (r *racesRepo) Facets(ctx context.Context, filter *racing.ListRacesRequestFilter) (_ *racing.RaceFacets, err error) {
+	where, filterArgs := filterClause(filter)
+	query := fmt.Sprintf(getRaceQueries()[racesFacets], where)
+	args := append([]interface{}{
+		int32(racing.RaceStatus_ABANDONED),
+		time.Now().UTC().Format(time.RFC3339),
+		int32(racing.RaceStatus_CLOSED),
+		int32(racing.RaceStatus_DELAYED),
+		int32(racing.RaceStatus_OPEN),
+	}, filterArgs...)
+
+	ctx, done := r.startQuery(ctx, "facets", "racesRepo.Facets", query)
+	defer func() { done(&err) }()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	var (
+		facets   racing.RaceFacets
+		meetings = map[int64]int64{}
+		statuses = map[racing.RaceStatus]int64{}
+	)
+	for rows.Next() {
+		var (
+			meetingID, count int64
+			status           int32
+			visible          bool
+		)
+		if err := rows.Scan(&meetingID, &status, &visible, &count); err != nil {
+			return nil, err
+		}
 
-	// Validate and default sortField
-	if !allowedOrderFields[sortField] {
-		sortField = "advertised_start_time"
+		facets.TotalCount += count
+		meetings[meetingID] += count
+		statuses[racing.RaceStatus(status)] += count
+		if visible {
+			facets.VisibleCount += count
+		} else {
+			facets.HiddenCount += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	// Validate and default sortDirection
-	sortDirection = strings.ToUpper(sortDirection)
-	if sortDirection != "DESC" {
-		sortDirection = "ASC"
+	for meetingID, count := range meetings {
+		facets.Meetings = append(facets.Meetings, &racing.MeetingCount{MeetingId: meetingID, Count: count})
+	}
+	sort.Slice(facets.Meetings, func(i, j int) bool { return facets.Meetings[i].MeetingId < facets.Meetings[j].MeetingId })
+	for status, count := range statuses {
+		facets.Statuses = append(facets.Statuses, &racing.StatusCount{Status: status, Count: count})
 	}
+	sort.Slice(facets.Statuses, func(i, j int) bool { return facets.Statuses[i].Status < facets.Statuses[j].Status })
 
-	query += " ORDER BY " + sortField + " " + sortDirection
+	return &facets, nil
+}
+
+// GetByID fetches the given fields of a single Race by its ID.
+func (r *racesRepo) GetByID(ctx context.Context, id int64, fields RaceFields) (_ *racing.Race, err error) {
+	columns := fields.columns()
+	query := fmt.Sprintf(getRaceQueries()[racesGet], selectList(columns))
+	ctx, done := r.startQuery(ctx, "get", "racesRepo.GetByID", query)
+	defer func() { done(&err) }()
 
-	return query, args
+	return fields.scan(r.db.QueryRowContext(ctx, query, id), columns)
 }
 
-func (m *racesRepo) scanRaces(
-	rows *sql.Rows,
-) ([]*racing.Race, error) {
-	var races []*racing.Race
+// GetByIDs fetches the given fields of the races with ids in a single query.
+// Races that do not exist are left out.
+func (r *racesRepo) GetByIDs(ctx context.Context, ids []int64, fields RaceFields) (races []*racing.Race, err error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 
-	for rows.Next() {
-		var race racing.Race
-		var advertisedStart time.Time
+	columns := fields.columns()
+	query := fmt.Sprintf(getRaceQueries()[racesGetMany], selectList(columns), strings.Repeat("?,", len(ids)-1)+"?")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
 
-		if err := rows.Scan(&race.Id, &race.MeetingId, &race.Name, &race.Number, &race.Visible, &advertisedStart); err != nil {
-			if err == sql.ErrNoRows {
-				return nil, nil
-			}
+	ctx, done := r.startQuery(ctx, "get_many", "racesRepo.GetByIDs", query)
+	defer func() { done(&err) }()
 
-			return nil, err
-		}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		ts, err := ptypes.TimestampProto(advertisedStart)
+	for rows.Next() {
+		race, err := fields.scan(rows, columns)
 		if err != nil {
 			return nil, err
 		}
+		races = append(races, race)
+	}
 
-		race.AdvertisedStartTime = ts
-		// Derive OPEN/CLOSED status
-		if advertisedStart.Before(time.Now()) {
-			race.Status = racing.RaceStatus_CLOSED
-		} else {
-			race.Status = racing.RaceStatus_OPEN
-		}
-		races = append(races, &race)
+	return races, rows.Err()
+}
+
+// deriveStatus works out a race's status from its start time and schedule flags.
+// Abandonment wins over everything; otherwise a race that has started is CLOSED
+// and a delayed race that has not started is DELAYED.
+func deriveStatus(advertisedStart time.Time, abandoned, delayed bool) racing.RaceStatus {
+	switch {
+	case abandoned:
+		return racing.RaceStatus_ABANDONED
+	case advertisedStart.Before(time.Now()):
+		return racing.RaceStatus_CLOSED
+	case delayed:
+		return racing.RaceStatus_DELAYED
+	default:
+		return racing.RaceStatus_OPEN
 	}
+}
 
-	return races, nil
+// Delay moves a race to a later start time.
+func (r *racesRepo) Delay(ctx context.Context, id int64, newStart time.Time, reason string) (*racing.Race, error) {
+	return r.changeSchedule(ctx, id, racing.ScheduleChangeType_SCHEDULE_CHANGE_DELAY, &newStart, reason)
+}
+
+// Abandon marks a race as abandoned. Its start time is left untouched.
+func (r *racesRepo) Abandon(ctx context.Context, id int64, reason string) (*racing.Race, error) {
+	return r.changeSchedule(ctx, id, racing.ScheduleChangeType_SCHEDULE_CHANGE_ABANDON, nil, reason)
+}
+
+// Reinstate clears a race's abandonment. When newStart is nil the race keeps its
+// existing start time.
+func (r *racesRepo) Reinstate(ctx context.Context, id int64, newStart *time.Time, reason string) (*racing.Race, error) {
+	return r.changeSchedule(ctx, id, racing.ScheduleChangeType_SCHEDULE_CHANGE_REINSTATE, newStart, reason)
 }
 
-// GetByID fetches a single Race by its ID.
-func (r *racesRepo) GetByID(id int64) (*racing.Race, error) {
-	row := r.db.QueryRow(`SELECT id, meeting_id, name, number, visible, advertised_start_time FROM races WHERE id = ?`, id)
+// changeSchedule applies a schedule change to a race and records it in
+// race_schedule_history within a single transaction.
+func (r *racesRepo) changeSchedule(ctx context.Context, id int64, changeType racing.ScheduleChangeType, newStart *time.Time, reason string) (_ *racing.Race, err error) {
+	ctx, done := r.startQuery(ctx, "schedule", "racesRepo.changeSchedule", raceScheduleQuery)
+	defer func() { done(&err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := applyScheduleChange(ctx, tx, id, changeType, newStart, reason); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id, nil)
+}
+
+// raceScheduleQuery reads the parts of a race a schedule change looks at.
+const raceScheduleQuery = `SELECT advertised_start_time, abandoned, delayed FROM races WHERE id = ?`
+
+// applyScheduleChange applies a schedule change to a race within tx and
+// records it in race_schedule_history.
+func applyScheduleChange(ctx context.Context, tx *sql.Tx, id int64, changeType racing.ScheduleChangeType, newStart *time.Time, reason string) error {
 	var (
-		race            racing.Race
-		advertisedStart time.Time
+		previousStart      time.Time
+		abandoned, delayed bool
 	)
-	if err := row.Scan(
-		&race.Id,
-		&race.MeetingId,
-		&race.Name,
-		&race.Number,
-		&race.Visible,
-		&advertisedStart,
+	if err := tx.QueryRowContext(ctx, raceScheduleQuery, id).
+		Scan(&previousStart, &abandoned, &delayed); err != nil {
+		return err
+	}
+
+	start := previousStart
+	if newStart != nil {
+		start = *newStart
+	}
+
+	switch changeType {
+	case racing.ScheduleChangeType_SCHEDULE_CHANGE_DELAY:
+		if abandoned {
+			return ErrRaceAbandoned
+		}
+		if !start.After(previousStart) {
+			return ErrDelayNotLater
+		}
+		delayed = true
+	case racing.ScheduleChangeType_SCHEDULE_CHANGE_ABANDON:
+		if abandoned {
+			return ErrRaceAbandoned
+		}
+		abandoned = true
+	case racing.ScheduleChangeType_SCHEDULE_CHANGE_REINSTATE:
+		if !abandoned {
+			return ErrRaceNotAbandoned
+		}
+		abandoned = false
+		if start.After(previousStart) {
+			delayed = true
+		}
+	case racing.ScheduleChangeType_SCHEDULE_CHANGE_IMPORT:
+		// Once a trader has changed the schedule, the feed no longer sets it.
+		if abandoned {
+			return ErrRaceAbandoned
+		}
+		if delayed {
+			return ErrRaceDelayed
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE races SET advertised_start_time = ?, abandoned = ?, delayed = ? WHERE id = ?`,
+		start.UTC().Format(time.RFC3339), abandoned, delayed, id,
 	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO race_schedule_history(race_id, change_type, previous_start_time, new_start_time, reason, changed_at) VALUES (?,?,?,?,?,?)`,
+		id,
+		int32(changeType),
+		previousStart.UTC().Format(time.RFC3339),
+		start.UTC().Format(time.RFC3339),
+		reason,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// ScheduleHistory returns the schedule changes made to a race, oldest first.
+func (r *racesRepo) ScheduleHistory(ctx context.Context, id int64) (_ []*racing.ScheduleChange, err error) {
+	query := getRaceQueries()[racesScheduleHistory]
+	ctx, done := r.startQuery(ctx, "history", "racesRepo.ScheduleHistory", query)
+	defer func() { done(&err) }()
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var history []*racing.ScheduleChange
+
+	for rows.Next() {
+		var (
+			change                             racing.ScheduleChange
+			changeType                         int32
+			previousStart, newStart, changedAt time.Time
+		)
 
-	// Timestamp conversion
-	ts, err := ptypes.TimestampProto(advertisedStart)
+		if err := rows.Scan(&change.Id, &change.RaceId, &changeType, &previousStart, &newStart, &change.Reason, &changedAt); err != nil {
+			return nil, err
+		}
+
+		change.Type = racing.ScheduleChangeType(changeType)
+		change.PreviousStartTime = timestamppb.New(previousStart)
+		change.NewStartTime = timestamppb.New(newStart)
+		change.ChangedAt = timestamppb.New(changedAt)
+		history = append(history, &change)
+	}
+
+	return history, rows.Err()
+}
+
+// Search matches query against the races_fts index of race and meeting names
+// and returns the best limit of the matches, ranked by BM25. Race name matches
+// count double.
+func (r *racesRepo) Search(ctx context.Context, query string, limit int) (_ []*racing.SearchResult, err error) {
+	if !fts.Enabled {
+		return nil, fts.ErrUnavailable
+	}
+
+	match := fts.MatchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	sqlQuery := getRaceQueries()[racesSearch]
+	ctx, done := r.startQuery(ctx, "search", "racesRepo.Search", sqlQuery)
+	defer func() { done(&err) }()
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, match, limit)
 	if err != nil {
 		return nil, err
 	}
-	race.AdvertisedStartTime = ts
+	defer rows.Close()
+
+	var results []*racing.SearchResult
+
+	for rows.Next() {
+		var (
+			race               racing.Race
+			advertisedStart    time.Time
+			abandoned, delayed bool
+			result             racing.SearchResult
+			rank               float64
+		)
+
+		if err := rows.Scan(&race.Id, &race.MeetingId, &race.Name, &race.Number, &race.Visible, &advertisedStart, &abandoned, &delayed, &result.Snippet, &rank); err != nil {
+			return nil, err
+		}
 
-	// Re-use Task 3 logic:
-	if advertisedStart.Before(time.Now()) {
-		race.Status = racing.RaceStatus_CLOSED
-	} else {
-		race.Status = racing.RaceStatus_OPEN
+		race.AdvertisedStartTime = timestamppb.New(advertisedStart)
+		race.Status = deriveStatus(advertisedStart, abandoned, delayed)
+		result.Race = &race
+		result.Score = -rank
+		results = append(results, &result)
 	}
 
-	return &race, nil
+	return results, rows.Err()
 }
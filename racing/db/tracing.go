@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("git.neds.sh/matty/entain/racing/db")
+
+// startSpan starts a client span named name for running query. The query text
+// is recorded without its arguments, so no request data ends up in traces.
+func startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemSqlite,
+			semconv.DBQueryText(strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+// endSpan marks span as failed if err is set, then ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,272 @@
+// Package feed reads the race schedules upstream providers send as JSON or CSV
+// files into rows for ImportRaces.
+//
+// Both formats carry the same fields, named by these columns or keys:
+//
+//   - meeting_id and meeting_name, the provider's ID and name for the meeting.
+//   - race_id, race_name and race_number, the provider's ID for the race, its
+//     name and its number within the meeting.
+//   - start_time, the advertised start time in RFC 3339.
+//   - visible, optional: true or false. A race is visible by default.
+//
+// A CSV file starts with a header row naming its columns, in any order. A JSON
+// file is an array of objects, whose IDs may be strings or numbers. Other
+// columns and keys are ignored.
+package feed
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Format is the file format of a feed.
+type Format string
+
+// Supported formats.
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// ParseFormat returns the format named s, "json" or "csv". An empty s picks
+// the format from path's extension.
+func ParseFormat(s, path string) (Format, error) {
+	if s == "" {
+		s = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format := Format(strings.ToLower(s)); format {
+	case FormatJSON, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("feed: unknown format %q, want json or csv", s)
+	}
+}
+
+// Columns of a feed.
+const (
+	columnMeetingID   = "meeting_id"
+	columnMeetingName = "meeting_name"
+	columnRaceID      = "race_id"
+	columnRaceName    = "race_name"
+	columnRaceNumber  = "race_number"
+	columnStartTime   = "start_time"
+	columnVisible     = "visible"
+)
+
+// columnFields maps each column to the ImportRaceRow field it fills, which
+// errors are reported against.
+var columnFields = map[string]string{
+	columnMeetingID:   "meeting_external_id",
+	columnMeetingName: "meeting_name",
+	columnRaceID:      "external_id",
+	columnRaceName:    "name",
+	columnRaceNumber:  "number",
+	columnStartTime:   "advertised_start_time",
+	columnVisible:     "visible",
+}
+
+// requiredColumns are the columns every CSV feed must have.
+var requiredColumns = []string{columnMeetingID, columnMeetingName, columnRaceID, columnRaceName, columnRaceNumber, columnStartTime}
+
+// Reader reads rows from a feed.
+type Reader struct {
+	// next returns the next row's values by column, io.EOF after the last
+	// row, or an error for a row that cannot be read at all.
+	next func() (map[string]string, error)
+	row  int64
+}
+
+// NewReader returns a reader of the feed in r. For CSV it reads the header row,
+// failing if a required column is missing.
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSON:
+		return newJSONReader(r)
+	default:
+		return nil, fmt.Errorf("feed: unknown format %q", format)
+	}
+}
+
+// Read returns the next row, numbered from 1 in file order, or io.EOF after
+// the last one. A row with values that cannot be parsed is returned as the
+// errors found in it instead, with a nil row, and reading can carry on. Any
+// other error means the rest of the feed cannot be read.
+func (r *Reader) Read() (*racing.ImportRaceRow, []*racing.ImportRowError, error) {
+	values, err := r.next()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, io.EOF
+	}
+	r.row++
+	var rowErr *rowError
+	if errors.As(err, &rowErr) {
+		return nil, []*racing.ImportRowError{{Row: r.row, Field: rowErr.field, Description: rowErr.description}}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("feed: row %d: %w", r.row, err)
+	}
+
+	return parseRow(r.row, values)
+}
+
+// rowError is a row that cannot be parsed, though the rows after it can.
+type rowError struct {
+	field, description string
+}
+
+func (e *rowError) Error() string { return e.field + ": " + e.description }
+
+// parseRow turns a row's values into an ImportRaceRow, or the errors in them.
+// Values that are missing are left for ImportRaces to reject.
+func parseRow(row int64, values map[string]string) (*racing.ImportRaceRow, []*racing.ImportRowError, error) {
+	var errs []*racing.ImportRowError
+	add := func(field, description string) {
+		errs = append(errs, &racing.ImportRowError{Row: row, Field: field, Description: description})
+	}
+
+	parsed := &racing.ImportRaceRow{
+		Row:               row,
+		MeetingExternalId: values[columnMeetingID],
+		MeetingName:       values[columnMeetingName],
+		ExternalId:        values[columnRaceID],
+		Name:              values[columnRaceName],
+		Visible:           true,
+	}
+	if s := values[columnRaceNumber]; s != "" {
+		number, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			add(columnFields[columnRaceNumber], fmt.Sprintf("%q is not a whole number", s))
+		}
+		parsed.Number = number
+	}
+	if s := values[columnStartTime]; s != "" {
+		start, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			add(columnFields[columnStartTime], fmt.Sprintf("%q is not an RFC 3339 time", s))
+		}
+		parsed.AdvertisedStartTime = timestamppb.New(start)
+	}
+	if s := values[columnVisible]; s != "" {
+		visible, err := strconv.ParseBool(s)
+		if err != nil {
+			add(columnFields[columnVisible], fmt.Sprintf("%q is not true or false", s))
+		}
+		parsed.Visible = visible
+	}
+
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+	return parsed, nil, nil
+}
+
+func newCSVReader(r io.Reader) (*Reader, error) {
+	records := csv.NewReader(r)
+	records.TrimLeadingSpace = true
+
+	header, err := records.Read()
+	if err != nil {
+		return nil, fmt.Errorf("feed: reading CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, column := range requiredColumns {
+		if !slices.Contains(header, column) {
+			return nil, fmt.Errorf("feed: CSV header has no %s column", column)
+		}
+	}
+
+	return &Reader{next: func() (map[string]string, error) {
+		record, err := records.Read()
+		if errors.Is(err, csv.ErrFieldCount) {
+			return nil, &rowError{field: "row", description: fmt.Sprintf("has %d columns, the header %d", len(record), len(header))}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = strings.TrimSpace(record[i])
+		}
+		return values, nil
+	}}, nil
+}
+
+func newJSONReader(r io.Reader) (*Reader, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	if token, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("feed: reading JSON: %w", err)
+	} else if token != json.Delim('[') {
+		return nil, errors.New("feed: JSON feed must be an array of races")
+	}
+
+	return &Reader{next: func() (map[string]string, error) {
+		if !dec.More() {
+			return nil, io.EOF
+		}
+
+		var object map[string]json.RawMessage
+		if err := dec.Decode(&object); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				return nil, &rowError{field: "row", description: "is not a JSON object"}
+			}
+			return nil, err
+		}
+
+		values := make(map[string]string, len(object))
+		for key, raw := range object {
+			column := strings.ToLower(key)
+			field, ok := columnFields[column]
+			if !ok {
+				continue
+			}
+			value, ok := scalar(raw)
+			if !ok {
+				return nil, &rowError{field: field, description: "must be a string, number or boolean"}
+			}
+			values[column] = strings.TrimSpace(value)
+		}
+		return values, nil
+	}}, nil
+}
+
+// scalar returns the text of a JSON string, number or boolean, and "" for
+// null. It reports false for arrays and objects.
+func scalar(raw json.RawMessage) (string, bool) {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
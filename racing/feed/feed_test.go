@@ -0,0 +1,150 @@
+package feed
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// readAll reads every row of the feed in s, returning the rows that parsed
+// and the errors in those that did not.
+func readAll(t *testing.T, s string, format Format) ([]*racing.ImportRaceRow, []*racing.ImportRowError) {
+	t.Helper()
+	r, err := NewReader(strings.NewReader(s), format)
+	require.NoError(t, err)
+
+	var (
+		rows []*racing.ImportRaceRow
+		errs []*racing.ImportRowError
+	)
+	for {
+		row, rowErrs, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, errs
+		}
+		require.NoError(t, err)
+		errs = append(errs, rowErrs...)
+		if row != nil {
+			rows = append(rows, row)
+		}
+	}
+}
+
+func assertRows(t *testing.T, want, got []*racing.ImportRaceRow) {
+	t.Helper()
+	if assert.Len(t, got, len(want)) {
+		for i := range want {
+			assert.True(t, proto.Equal(want[i], got[i]), "row %d: got %v", i+1, got[i])
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		format, path string
+		want         Format
+		wantErr      bool
+	}{
+		{format: "csv", path: "feed.json", want: FormatCSV},
+		{format: "JSON", want: FormatJSON},
+		{path: "feeds/tab.CSV", want: FormatCSV},
+		{path: "tab.json", want: FormatJSON},
+		{path: "tab.xml", wantErr: true},
+		{format: "xml", path: "tab.csv", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.format, tt.path)
+		if tt.wantErr {
+			assert.Error(t, err, "ParseFormat(%q, %q)", tt.format, tt.path)
+			continue
+		}
+		assert.NoError(t, err, "ParseFormat(%q, %q)", tt.format, tt.path)
+		assert.Equal(t, tt.want, got, "ParseFormat(%q, %q)", tt.format, tt.path)
+	}
+}
+
+func TestReader_CSV(t *testing.T) {
+	start := timestamppb.New(time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC))
+	rows, errs := readAll(t, strings.Join([]string{
+		"race_id, Race_Name, race_number, meeting_id, meeting_name, start_time, visible, notes",
+		"R1, Melbourne Cup, 7, M1, Flemington, 2030-01-01T10:00:00Z, false, ignored",
+		"R2, Oaks, 8, M1, Flemington, 2030-01-01T10:00:00Z, , ",
+		"R3, Sprint, seven, M1, Flemington, tomorrow, maybe, ",
+		"R4, Short, 1",
+		"R5, Derby, 9, M1, Flemington, 2030-01-01T10:00:00Z, true, ",
+	}, "\n"), FormatCSV)
+
+	assertRows(t, []*racing.ImportRaceRow{
+		{Row: 1, MeetingExternalId: "M1", MeetingName: "Flemington", ExternalId: "R1", Name: "Melbourne Cup", Number: 7, AdvertisedStartTime: start},
+		{Row: 2, MeetingExternalId: "M1", MeetingName: "Flemington", ExternalId: "R2", Name: "Oaks", Number: 8, Visible: true, AdvertisedStartTime: start},
+		{Row: 5, MeetingExternalId: "M1", MeetingName: "Flemington", ExternalId: "R5", Name: "Derby", Number: 9, Visible: true, AdvertisedStartTime: start},
+	}, rows)
+
+	type rowField struct {
+		row   int64
+		field string
+	}
+	var got []rowField
+	for _, e := range errs {
+		got = append(got, rowField{e.Row, e.Field})
+	}
+	assert.Equal(t, []rowField{
+		{3, "number"},
+		{3, "advertised_start_time"},
+		{3, "visible"},
+		{4, "row"},
+	}, got)
+}
+
+func TestReader_CSVMissingColumn(t *testing.T) {
+	_, err := NewReader(strings.NewReader("meeting_id,meeting_name,race_id,race_name,start_time\n"), FormatCSV)
+	assert.ErrorContains(t, err, "race_number")
+
+	_, err = NewReader(strings.NewReader(""), FormatCSV)
+	assert.Error(t, err, "a CSV feed needs a header")
+}
+
+func TestReader_JSON(t *testing.T) {
+	start := timestamppb.New(time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC))
+	rows, errs := readAll(t, `[
+		{"meeting_id": 12, "meeting_name": "Flemington", "race_id": 345, "race_name": "Melbourne Cup", "race_number": 7, "start_time": "2030-01-01T10:00:00Z", "silks": ["red"]},
+		{"meeting_id": "M1", "meeting_name": "Flemington", "race_id": "R2", "race_name": "Oaks", "race_number": "8", "start_time": "2030-01-01T10:00:00Z", "visible": false},
+		{"meeting_id": {"id": 1}, "race_id": "R3"},
+		"R4",
+		{"race_id": "R5", "race_number": 1.5}
+	]`, FormatJSON)
+
+	assertRows(t, []*racing.ImportRaceRow{
+		{Row: 1, MeetingExternalId: "12", MeetingName: "Flemington", ExternalId: "345", Name: "Melbourne Cup", Number: 7, Visible: true, AdvertisedStartTime: start},
+		{Row: 2, MeetingExternalId: "M1", MeetingName: "Flemington", ExternalId: "R2", Name: "Oaks", Number: 8, AdvertisedStartTime: start},
+	}, rows)
+
+	if assert.Len(t, errs, 3) {
+		assert.Equal(t, int64(3), errs[0].Row)
+		assert.Equal(t, "meeting_external_id", errs[0].Field)
+		assert.Equal(t, int64(4), errs[1].Row)
+		assert.Equal(t, "row", errs[1].Field)
+		assert.Equal(t, int64(5), errs[2].Row)
+		assert.Equal(t, "number", errs[2].Field)
+	}
+}
+
+func TestReader_JSONNotAnArray(t *testing.T) {
+	_, err := NewReader(strings.NewReader(`{"race_id": "R1"}`), FormatJSON)
+	assert.Error(t, err)
+
+	r, err := NewReader(strings.NewReader(`[{"race_id": "R1"}, {`), FormatJSON)
+	require.NoError(t, err)
+	_, _, err = r.Read()
+	assert.NoError(t, err)
+	_, _, err = r.Read()
+	assert.Error(t, err, "a truncated feed cannot be read on from")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"git.neds.sh/matty/entain/racing/feed"
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// importBatchSize is the number of rows sent in each ImportRaces message.
+const importBatchSize = 500
+
+// errImportRejected is returned by runImport when the feed had invalid rows,
+// once they have been reported.
+var errImportRejected = errors.New("import rejected")
+
+// importConfig is the configuration of "racing import", which streams a
+// provider's feed file to a running racing service. It is loaded like Config,
+// from RACING_IMPORT_* environment variables and flags.
+type importConfig struct {
+	Addr     string `yaml:"addr" flag:"grpc-endpoint" usage:"racing gRPC server to import into"`
+	File     string `yaml:"file" flag:"file" usage:"provider feed file to import"`
+	Format   string `yaml:"format" flag:"format" usage:"feed format, json or csv; empty picks it from the file extension"`
+	Provider string `yaml:"provider" flag:"provider" usage:"name of the provider the feed comes from"`
+	DryRun   bool   `yaml:"dry_run" flag:"dry-run" usage:"report what the import would change without writing it"`
+	// Token is left out of the file and of -print-config.
+	Token   string        `yaml:"-" flag:"token" usage:"bearer token of a caller with the trader role"`
+	Timeout time.Duration `yaml:"timeout" flag:"timeout" usage:"how long the import may take"`
+	TLS     importTLS     `yaml:"tls"`
+}
+
+// importTLS is how "racing import" dials the racing service over TLS.
+// CertFile and KeyFile are a client certificate, for services that require
+// mutual TLS.
+type importTLS struct {
+	Enabled    bool   `yaml:"enabled" flag:"tls" usage:"dial the racing service over TLS"`
+	CAFile     string `yaml:"ca_file" flag:"tls-ca" usage:"PEM CA bundle to verify the racing service with; empty uses the system roots"`
+	CertFile   string `yaml:"cert_file" flag:"tls-cert" usage:"PEM client certificate to present to the racing service"`
+	KeyFile    string `yaml:"key_file" flag:"tls-key" usage:"PEM private key for the client certificate"`
+	ServerName string `yaml:"server_name" flag:"tls-server-name" usage:"name to verify the racing service's certificate against; empty uses the dialled host"`
+}
+
+func defaultImportConfig() importConfig {
+	return importConfig{
+		Addr:    "localhost:9000",
+		Timeout: 5 * time.Minute,
+	}
+}
+
+func (c *importConfig) Validate() error {
+	if c.File == "" {
+		return errors.New("file is required")
+	}
+	if c.Provider == "" {
+		return errors.New("provider is required")
+	}
+	if _, err := feed.ParseFormat(c.Format, c.File); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	return config.ValidateAddress("addr", c.Addr)
+}
+
+func (t *importTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("tls.cert_file and tls.key_file must be set together")
+	}
+	if !t.Enabled && (t.CAFile != "" || t.CertFile != "") {
+		return errors.New("tls files are set but tls.enabled is false")
+	}
+	return nil
+}
+
+// runImport reads the feed file named in args, streams its rows to
+// ImportRaces and writes a report of the result to w.
+//
+// Rows that cannot be parsed are reported along with the rows ImportRaces
+// rejects. They would be missing from the import, so when there are any the
+// rest of the feed is only checked, as a dry run.
+func runImport(args []string, w io.Writer) error {
+	cfg := defaultImportConfig()
+	printConfig, err := config.Load("RACING_IMPORT", &cfg, args)
+	if err != nil {
+		return err
+	}
+	if printConfig {
+		return config.Print(w, cfg)
+	}
+
+	rows, parseErrs, err := readFeed(cfg.File, cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS.Enabled {
+		certs, err := tlsconfig.NewReloader(tlsconfig.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+		if err != nil {
+			return err
+		}
+		creds = certs.ClientCredentials(cfg.TLS.ServerName)
+	}
+
+	conn, err := grpc.NewClient(cfg.Addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	if cfg.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+cfg.Token)
+	}
+
+	resp, err := sendImport(ctx, racing.NewRacingClient(conn), cfg.Provider, cfg.DryRun || len(parseErrs) > 0, rows)
+	if err != nil {
+		return err
+	}
+
+	writeImportReport(w, parseErrs, resp)
+	if len(parseErrs) > 0 || len(resp.Errors) > 0 {
+		return errImportRejected
+	}
+	return nil
+}
+
+// readFeed reads every row of the feed file at path, returning the rows that
+// parsed and the errors in those that did not.
+func readFeed(path, format string) ([]*racing.ImportRaceRow, []*racing.ImportRowError, error) {
+	feedFormat, err := feed.ParseFormat(format, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	reader, err := feed.NewReader(f, feedFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		rows []*racing.ImportRaceRow
+		errs []*racing.ImportRowError
+	)
+	for {
+		row, rowErrs, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, errs, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		errs = append(errs, rowErrs...)
+		if row != nil {
+			rows = append(rows, row)
+		}
+	}
+}
+
+// sendImport streams rows to ImportRaces in batches. The first message names
+// the provider and whether this is a dry run, and is sent even with no rows.
+func sendImport(ctx context.Context, client racing.RacingClient, provider string, dryRun bool, rows []*racing.ImportRaceRow) (*racing.ImportRacesResponse, error) {
+	stream, err := client.ImportRaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &racing.ImportRacesRequest{Provider: provider, DryRun: dryRun}
+	for start := 0; start == 0 || start < len(rows); start += importBatchSize {
+		req.Rows = rows[start:min(start+importBatchSize, len(rows))]
+		if err := stream.Send(req); err != nil {
+			// The server ended the stream early; CloseAndRecv returns why.
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		req = &racing.ImportRacesRequest{}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// writeImportReport writes the errors in the feed, or the changes made and
+// any warnings, then the summary and whether anything was written.
+func writeImportReport(w io.Writer, parseErrs []*racing.ImportRowError, resp *racing.ImportRacesResponse) {
+	errs := append(append([]*racing.ImportRowError(nil), parseErrs...), resp.Errors...)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+	for _, e := range errs {
+		fmt.Fprintf(w, "row %d: %s: %s\n", e.Row, e.Field, e.Description)
+	}
+
+	for _, change := range resp.Changes {
+		action := "create"
+		if change.Action == racing.ImportAction_IMPORT_ACTION_UPDATE {
+			action = "update"
+		}
+		fields := make([]string, len(change.Fields))
+		for i, field := range change.Fields {
+			fields[i] = fmt.Sprintf("%s %q", field.Field, field.NewValue)
+			if action == "update" {
+				fields[i] = fmt.Sprintf("%s %q -> %q", field.Field, field.OldValue, field.NewValue)
+			}
+		}
+		fmt.Fprintf(w, "row %d: %s %s %q (id %d): %s\n", change.Row, action, change.Kind, change.ExternalId, change.Id, strings.Join(fields, ", "))
+	}
+	for _, warning := range resp.Warnings {
+		fmt.Fprintf(w, "row %d: warning: %s: %s\n", warning.Row, warning.Field, warning.Description)
+	}
+
+	s := resp.GetSummary()
+	fmt.Fprintf(w, "%d rows: meetings %d created, %d updated, %d unchanged; races %d created, %d updated, %d unchanged\n",
+		s.GetRows()+int64(len(parseErrs)),
+		s.GetMeetingsCreated(), s.GetMeetingsUpdated(), s.GetMeetingsUnchanged(),
+		s.GetRacesCreated(), s.GetRacesUpdated(), s.GetRacesUnchanged())
+
+	switch {
+	case len(errs) > 0:
+		fmt.Fprintf(w, "%d errors, nothing was written\n", len(errs))
+	case !resp.Applied:
+		fmt.Fprintln(w, "dry run, nothing was written")
+	default:
+		fmt.Fprintln(w, "import applied")
+	}
+}
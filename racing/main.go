@@ -1,59 +1,216 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"flag"
+	"errors"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"time"
 
 	"git.neds.sh/matty/entain/racing/db"
-	"git.neds.sh/matty/entain/racing/proto/racing"
 	"git.neds.sh/matty/entain/racing/service"
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/common/health"
+	"github.com/SylvanSol/Entain_Test/common/logging"
+	"github.com/SylvanSol/Entain_Test/common/metrics"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/SylvanSol/Entain_Test/common/shutdown"
+	"github.com/SylvanSol/Entain_Test/common/tlsconfig"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+	"github.com/SylvanSol/Entain_Test/common/validate"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"google.golang.org/grpc"
-)
-
-var (
-	grpcEndpoint = flag.String("grpc-endpoint", "localhost:9000", "gRPC server endpoint")
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
-	flag.Parse()
+	// "racing import" streams a feed file to a running racing service rather
+	// than serving.
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:], os.Stdout); err != nil {
+			if !errors.Is(err, errImportRejected) {
+				log.Printf("import failed: %s\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg := defaultConfig()
+	printConfig, err := config.Load("RACING", &cfg, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %s\n", err)
+	}
+	if printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatalf("failed printing configuration: %s\n", err)
+		}
+		return
+	}
+
+	slog.SetDefault(logging.New(os.Stderr, cfg.Logging.SlogLevel()))
 
-	if err := run(); err != nil {
-		log.Fatalf("failed running grpc server: %s\n", err)
+	if err := run(cfg); err != nil {
+		slog.Error("failed running grpc server", "error", err)
+		os.Exit(1)
 	}
 }
 
-func run() error {
-	conn, err := net.Listen("tcp", ":9000")
+func run(cfg Config) error {
+	conn, err := net.Listen("tcp", cfg.Listen)
 	if err != nil {
 		return err
 	}
 
-	racingDB, err := sql.Open("sqlite3", "./db/racing.db")
+	racingDB, err := sql.Open("sqlite3", cfg.DB.DSN)
 	if err != nil {
 		return err
 	}
+	defer racingDB.Close()
 
-	racesRepo := db.NewRacesRepo(racingDB)
+	reg := metrics.NewRegistry()
+	grpcMetrics := metrics.NewGRPCServer(reg)
+	queryTimer := metrics.NewQueryTimer(reg, "racing")
+
+	repoOpts := []db.Option{
+		db.WithQueryObserver(queryTimer.Observe),
+		db.WithQueryTimeout(cfg.DB.QueryTimeout),
+	}
+	if cfg.DB.Seed == config.SeedNone {
+		repoOpts = append(repoOpts, db.WithoutDummyData())
+	}
+
+	racesRepo := db.NewRacesRepo(racingDB, repoOpts...)
 	if err := racesRepo.Init(); err != nil {
 		return err
 	}
+	if !fts.Enabled {
+		slog.Warn("built without -tags sqlite_fts5; Search returns UNIMPLEMENTED")
+	}
+
+	reg.MustRegister(
+		collectors.NewDBStatsCollector(racingDB, "racing"),
+		newRaceStatusCollector(racesRepo),
+	)
 
-	grpcServer := grpc.NewServer()
+	ctx, stop := shutdown.OnSignal()
+	defer stop()
+
+	flushTraces, err := tracing.Setup(ctx, "racing", cfg.Tracing.Exporter, cfg.Tracing.Endpoint)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// ctx is done by the time we get here, so flush on a fresh deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := flushTraces(ctx); err != nil {
+			slog.Error("failed flushing trace spans", "error", err)
+		}
+	}()
+
+	var verifier *auth.Verifier
+	if cfg.Auth.JWKSFile != "" {
+		verifier, err = auth.NewVerifier(cfg.Auth.JWKSFile, cfg.Auth.Issuer, cfg.Auth.Audience)
+		if err != nil {
+			return err
+		}
+	} else {
+		slog.Warn("no JWKS configured; bearer tokens are rejected and only public RPCs can be called")
+	}
+
+	trustedProxies, err := ratelimit.ParsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	limiter := ratelimit.New(cfg.RateLimit.Default(), cfg.RateLimit.Routes)
+	apiKeys := cfg.RateLimit.Keys()
+	go limiter.Run(ctx, time.Minute)
+
+	serverOpts := []grpc.ServerOption{
+		tracing.ServerHandler(),
+		// Log first, so everything after has the request id and refused RPCs
+		// are logged and counted too. Rate limit next, as the cheapest check.
+		// Authorise before validating, so anonymous callers learn nothing
+		// about the RPCs they may not call.
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(slog.Default()),
+			grpcMetrics.UnaryServerInterceptor(),
+			ratelimit.UnaryServerInterceptor(limiter, apiKeys, trustedProxies, healthpb.Health_Check_FullMethodName),
+			auth.UnaryServerInterceptor(verifier, service.AuthPolicy()),
+			validate.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(slog.Default()),
+			grpcMetrics.StreamServerInterceptor(),
+			ratelimit.StreamServerInterceptor(limiter, apiKeys, trustedProxies, healthpb.Health_Watch_FullMethodName),
+			auth.StreamServerInterceptor(verifier, service.AuthPolicy()),
+			validate.StreamServerInterceptor(),
+		),
+	}
+	if cfg.TLS.Enabled() {
+		certs, err := tlsconfig.NewReloader(tlsconfig.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.ClientCAFile,
+		})
+		if err != nil {
+			return err
+		}
+		go certs.Run(ctx, tlsconfig.DefaultReloadInterval)
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certs.ServerConfig())))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 
 	racing.RegisterRacingServer(
 		grpcServer,
 		service.NewRacingService(
 			racesRepo,
+			service.WithListCache(cfg.ListCacheTTL),
 		),
 	)
 
-	log.Printf("gRPC server listening on: %s\n", *grpcEndpoint)
+	reporter := health.Register(grpcServer, racingDB.PingContext, racing.Racing_ServiceDesc.ServiceName)
+	go reporter.Run(ctx, health.DefaultInterval)
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- grpcServer.Serve(conn)
+	}()
+
+	slog.Info("gRPC server listening", "address", cfg.Listen)
 
-	if err := grpcServer.Serve(conn); err != nil {
+	var metricsServer *http.Server
+	if cfg.Metrics.Listen != "" {
+		metricsServer = metrics.NewServer(cfg.Metrics.Listen, reg)
+		go func() {
+			serveErr <- metricsServer.ListenAndServe()
+		}()
+		slog.Info("metrics listening", "url", "http://"+cfg.Metrics.Listen+"/metrics")
+	}
+
+	select {
+	case err := <-serveErr:
 		return err
+	case <-ctx.Done():
 	}
 
-	return nil
+	// Report NOT_SERVING before draining so probes stop routing traffic here.
+	slog.Info("shutting down, waiting for in-flight RPCs", "timeout", cfg.Timeouts.Shutdown.String())
+	reporter.Shutdown()
+
+	err = shutdown.GRPC(grpcServer, cfg.Timeouts.Shutdown)
+	if metricsServer != nil {
+		err = errors.Join(err, shutdown.HTTP(metricsServer, cfg.Timeouts.Shutdown))
+	}
+	return err
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+
+	"git.neds.sh/matty/entain/racing/db"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// raceStatusCollector reports racing_races{status}, the number of races in each
+// status. Races are counted when scraped, so the gauge is never stale.
+type raceStatusCollector struct {
+	repo db.RacesRepo
+	desc *prometheus.Desc
+}
+
+func newRaceStatusCollector(repo db.RacesRepo) *raceStatusCollector {
+	return &raceStatusCollector{
+		repo: repo,
+		desc: prometheus.NewDesc("racing_races", "Races by status.", []string{"status"}, nil),
+	}
+}
+
+func (c *raceStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *raceStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	races, err := c.repo.List(context.Background(), nil, nil, nil, db.Page{})
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	// Report every status, even when no race is in it.
+	counts := make(map[racing.RaceStatus]int, len(racing.RaceStatus_name))
+	for status := range racing.RaceStatus_name {
+		if racing.RaceStatus(status) != racing.RaceStatus_UNSPECIFIED {
+			counts[racing.RaceStatus(status)] = 0
+		}
+	}
+	for _, race := range races {
+		counts[race.Status]++
+	}
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), status.String())
+	}
+}
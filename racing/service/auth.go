@@ -0,0 +1,37 @@
+package service
+
+import (
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Roles recognised by the racing service.
+const (
+	// RoleInternal may see races that are hidden from the public.
+	RoleInternal = "internal"
+	// RoleTrader may delay, abandon, reinstate and import races.
+	RoleTrader = "trader"
+)
+
+// AuthPolicy says who may call each racing RPC. Listing, fetching and searching
+// races is public, but only internal callers may list hidden races.
+func AuthPolicy() auth.Policy {
+	return auth.Policy{
+		racing.Racing_ListRaces_FullMethodName: {ForRequest: func(req any) []string {
+			if !req.(*racing.ListRacesRequest).GetFilter().GetOnlyVisible() {
+				return []string{RoleInternal}
+			}
+			return nil
+		}},
+		racing.Racing_GetRace_FullMethodName:       auth.Public,
+		racing.Racing_BatchGetRaces_FullMethodName: auth.Public,
+		racing.Racing_Search_FullMethodName:        auth.Public,
+		racing.Racing_DelayRace_FullMethodName:     {Roles: []string{RoleTrader}},
+		racing.Racing_AbandonRace_FullMethodName:   {Roles: []string{RoleTrader}},
+		racing.Racing_ReinstateRace_FullMethodName: {Roles: []string{RoleTrader}},
+		racing.Racing_ImportRaces_FullMethodName:   {Roles: []string{RoleTrader}},
+		healthpb.Health_Check_FullMethodName:       auth.Public,
+		healthpb.Health_Watch_FullMethodName:       auth.Public,
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthPolicy_CoversEveryRPC(t *testing.T) {
+	policy := AuthPolicy()
+	for _, method := range racing.Racing_ServiceDesc.Methods {
+		fullMethod := "/" + racing.Racing_ServiceDesc.ServiceName + "/" + method.MethodName
+		assert.Contains(t, policy, fullMethod, "a new RPC needs a rule, or nobody can call it")
+	}
+	for _, stream := range racing.Racing_ServiceDesc.Streams {
+		fullMethod := "/" + racing.Racing_ServiceDesc.ServiceName + "/" + stream.StreamName
+		if assert.Contains(t, policy, fullMethod, "a new RPC needs a rule, or nobody can call it") {
+			assert.Nil(t, policy[fullMethod].ForRequest, "streams are denied to everyone by a rule with ForRequest")
+		}
+	}
+}
+
+func TestAuthPolicy_ListRaces(t *testing.T) {
+	rule := AuthPolicy()[racing.Racing_ListRaces_FullMethodName]
+
+	tests := []struct {
+		name      string
+		req       *racing.ListRacesRequest
+		wantRoles []string
+	}{
+		{name: "visible only", req: &racing.ListRacesRequest{Filter: &racing.ListRacesRequestFilter{OnlyVisible: true}}},
+		{name: "hidden included", req: &racing.ListRacesRequest{Filter: &racing.ListRacesRequestFilter{}}, wantRoles: []string{RoleInternal}},
+		{name: "no filter", req: &racing.ListRacesRequest{}, wantRoles: []string{RoleInternal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, rule.Roles)
+			assert.Equal(t, tt.wantRoles, rule.ForRequest(tt.req))
+		})
+	}
+}
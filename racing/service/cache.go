@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxListCacheEntries bounds how many distinct lists are cached, since clients
+// choose the meeting ids that make up a key.
+const maxListCacheEntries = 1024
+
+// listCache is a read-through cache of race lists, keyed by filter and sorts.
+//
+// An entry lives for at most ttl, and never past the earliest future
+// advertised_start_time among its races: that is when a race's status next
+// flips from OPEN or DELAYED to CLOSED, so the cache can never serve a status
+// that has already changed. Writes to races call invalidate, which drops every
+// entry.
+//
+// Concurrent misses for the same key share one repository call.
+type listCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]listCacheEntry
+	// generation counts invalidations. A load that started before the latest
+	// one may have read the old rows, so its result is not stored.
+	generation uint64
+}
+
+type listCacheEntry struct {
+	races   []*racing.Race
+	expires time.Time
+}
+
+// loadFunc lists races from the repository on a cache miss.
+type loadFunc func(ctx context.Context) ([]*racing.Race, error)
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]listCacheEntry),
+	}
+}
+
+// list returns the races for filter and sorts, calling load on a miss. The
+// races returned are the caller's own to modify.
+func (c *listCache) list(ctx context.Context, filter *racing.ListRacesRequestFilter, sorts []*racing.Sort, load loadFunc) ([]*racing.Race, error) {
+	key := listCacheKey(filter, sorts)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return cloneRaces(entry.races), nil
+	}
+
+	// Callers waiting on the same load must not fail because the one that
+	// started it went away, so the load outlives its caller's cancellation. The
+	// repository's query timeout still bounds it.
+	flight := c.group.DoChan(key+"@"+strconv.FormatUint(generation, 10), func() (interface{}, error) {
+		races, err := load(context.WithoutCancel(ctx))
+		if err == nil {
+			c.store(key, generation, races)
+		}
+		return races, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-flight:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return cloneRaces(res.Val.([]*racing.Race)), nil
+	}
+}
+
+// store caches races under key unless the cache has been invalidated since the
+// load began.
+func (c *listCache) store(key string, generation uint64, races []*racing.Race) {
+	now := c.now()
+	expires := now.Add(c.ttl)
+	for _, race := range races {
+		if start := race.GetAdvertisedStartTime().AsTime(); start.After(now) && start.Before(expires) {
+			expires = start
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if len(c.entries) >= maxListCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= maxListCacheEntries {
+		// Every entry is live; make room by dropping any one of them.
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+
+	c.entries[key] = listCacheEntry{races: races, expires: expires}
+}
+
+// invalidate drops every cached list, and stops loads already under way from
+// caching what they read.
+func (c *listCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	clear(c.entries)
+}
+
+// listCacheKey normalises filter and sorts, so requests that list the same
+// races in the same order share an entry: meeting ids are sorted and
+// de-duplicated, sort fields and directions are lower-cased, and sorts
+// default as the repository's do.
+func listCacheKey(filter *racing.ListRacesRequestFilter, sorts []*racing.Sort) string {
+	var b strings.Builder
+
+	meetingIDs := slices.Clone(filter.GetMeetingIds())
+	slices.Sort(meetingIDs)
+	meetingIDs = slices.Compact(meetingIDs)
+
+	b.WriteString("meetings=")
+	for i, id := range meetingIDs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatInt(id, 10))
+	}
+
+	b.WriteString(";visible=")
+	b.WriteString(strconv.FormatBool(filter.GetOnlyVisible()))
+
+	// The repository orders an unsorted list by start time.
+	if len(sorts) == 0 {
+		sorts = []*racing.Sort{{Field: "advertised_start_time"}}
+	}
+
+	b.WriteString(";sorts=")
+	for i, sort := range sorts {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		direction := strings.ToLower(sort.GetDirection())
+		if direction == "" {
+			direction = "asc"
+		}
+		b.WriteString(strings.ToLower(sort.GetField()) + " " + direction)
+	}
+
+	return b.String()
+}
+
+// cloneRaces deep-copies races, so no caller can change what is cached.
+func cloneRaces(races []*racing.Race) []*racing.Race {
+	if races == nil {
+		return nil
+	}
+	clones := make([]*racing.Race, len(races))
+	for i, race := range races {
+		clones[i] = proto.Clone(race).(*racing.Race)
+	}
+	return clones
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"git.neds.sh/matty/entain/racing/db"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// countingLoad returns a loadFunc listing races, and the number of times it
+// has been called.
+func countingLoad(races ...*racing.Race) (loadFunc, *atomic.Int32) {
+	var calls atomic.Int32
+	return func(context.Context) ([]*racing.Race, error) {
+		calls.Add(1)
+		return races, nil
+	}, &calls
+}
+
+func TestListCache_Hit(t *testing.T) {
+	c := newListCache(time.Minute)
+	load, calls := countingLoad(&racing.Race{Id: 1, Name: "Cup"})
+	ctx := context.Background()
+
+	first, err := c.list(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{2, 1}}, nil, load)
+	assert.NoError(t, err)
+	first[0].Name = "changed by the caller"
+
+	// The same meeting ids in another order, repeated, and the default sort
+	// spelt out all list the same races.
+	second, err := c.list(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{1, 2, 1}}, []*racing.Sort{
+		{Field: "advertised_start_time", Direction: "ASC"},
+	}, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cup", second[0].Name, "callers get their own copies")
+	assert.EqualValues(t, 1, calls.Load())
+
+	_, err = c.list(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{1, 2}, OnlyVisible: true}, nil, load)
+	assert.NoError(t, err)
+	_, err = c.list(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{1, 2}}, []*racing.Sort{{Field: "name"}}, load)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, calls.Load(), "other filters and sorts are separate entries")
+
+	// Sort fields ignore case, as validation and the repository do.
+	_, err = c.list(ctx, &racing.ListRacesRequestFilter{MeetingIds: []int64{2, 1}}, []*racing.Sort{{Field: "Name", Direction: "Asc"}}, load)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, calls.Load(), "a mixed-case field is the same entry")
+}
+
+func TestListCache_ExpiresAtNextStart(t *testing.T) {
+	now := time.Now()
+	c := newListCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	load, calls := countingLoad(
+		&racing.Race{Id: 1, AdvertisedStartTime: timestamppb.New(now.Add(-time.Hour))},
+		&racing.Race{Id: 2, AdvertisedStartTime: timestamppb.New(now.Add(10 * time.Second))},
+	)
+	ctx := context.Background()
+
+	_, _ = c.list(ctx, nil, nil, load)
+	now = now.Add(9 * time.Second)
+	_, _ = c.list(ctx, nil, nil, load)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// Race 2 jumps, so its status is no longer OPEN.
+	now = now.Add(time.Second)
+	_, _ = c.list(ctx, nil, nil, load)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestListCache_ExpiresAfterTTL(t *testing.T) {
+	now := time.Now()
+	c := newListCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	load, calls := countingLoad(&racing.Race{Id: 1, AdvertisedStartTime: timestamppb.New(now.Add(time.Hour))})
+	ctx := context.Background()
+
+	_, _ = c.list(ctx, nil, nil, load)
+	now = now.Add(time.Minute)
+	_, _ = c.list(ctx, nil, nil, load)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestListCache_Errors(t *testing.T) {
+	c := newListCache(time.Minute)
+	var calls int
+	load := func(context.Context) ([]*racing.Race, error) {
+		calls++
+		return nil, errors.New("disk on fire")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.list(context.Background(), nil, nil, load)
+		assert.EqualError(t, err, "disk on fire")
+	}
+	assert.Equal(t, 2, calls, "errors are not cached")
+}
+
+func TestListCache_CoalescesMisses(t *testing.T) {
+	c := newListCache(time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) ([]*racing.Race, error) {
+		calls.Add(1)
+		<-release
+		return []*racing.Race{{Id: 1}}, nil
+	}
+
+	// The first caller gives up, which must not fail the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := c.list(ctx, nil, nil, load)
+		firstErr <- err
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			races, err := c.list(context.Background(), nil, nil, load)
+			if assert.NoError(t, err) {
+				assert.Len(t, races, 1)
+			}
+		}()
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestListCache_Invalidate(t *testing.T) {
+	c := newListCache(time.Minute)
+	load, calls := countingLoad(&racing.Race{Id: 1})
+	ctx := context.Background()
+
+	_, _ = c.list(ctx, nil, nil, load)
+	c.invalidate()
+	_, _ = c.list(ctx, nil, nil, load)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestListCache_InvalidateDuringLoad(t *testing.T) {
+	c := newListCache(time.Minute)
+	ctx := context.Background()
+
+	// The write lands while the list is being read, so what was read may be
+	// stale and must not be cached.
+	_, _ = c.list(ctx, nil, nil, func(context.Context) ([]*racing.Race, error) {
+		c.invalidate()
+		return []*racing.Race{{Id: 1, Name: "before"}}, nil
+	})
+
+	races, err := c.list(ctx, nil, nil, func(context.Context) ([]*racing.Race, error) {
+		return []*racing.Race{{Id: 1, Name: "after"}}, nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "after", races[0].Name)
+	}
+}
+
+// writeRepo is a RacesRepo whose list changes name when a race is delayed.
+type writeRepo struct {
+	db.RacesRepo
+	name  string
+	lists int
+}
+
+func (r *writeRepo) List(context.Context, *racing.ListRacesRequestFilter, []*racing.Sort, db.RaceFields, db.Page) ([]*racing.Race, error) {
+	r.lists++
+	return []*racing.Race{{Id: 1, Name: r.name}}, nil
+}
+
+func (r *writeRepo) Delay(context.Context, int64, time.Time, string) (*racing.Race, error) {
+	r.name = "delayed"
+	return &racing.Race{Id: 1, Name: r.name}, nil
+}
+
+func TestListRaces_CacheInvalidatedByWrites(t *testing.T) {
+	repo := &writeRepo{name: "scheduled"}
+	s := NewRacingService(repo, WithListCache(time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		resp, err := s.ListRaces(ctx, &racing.ListRacesRequest{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "scheduled", resp.Races[0].Name)
+		}
+	}
+	assert.Equal(t, 1, repo.lists)
+
+	_, err := s.DelayRace(ctx, &racing.DelayRaceRequest{Id: 1, NewStartTime: timestamppb.Now()})
+	assert.NoError(t, err)
+
+	resp, err := s.ListRaces(ctx, &racing.ListRacesRequest{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "delayed", resp.Races[0].Name)
+	}
+	assert.Equal(t, 2, repo.lists)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+)
+
+const (
+	// maxImportRows bounds the rows of one import, which are held in memory
+	// until the stream ends.
+	maxImportRows = 10000
+	// maxImportIDLen and maxImportNameLen bound the provider's IDs and the
+	// names in an imported row, in characters.
+	maxImportIDLen   = 100
+	maxImportNameLen = 200
+)
+
+func (s *racingService) ImportRaces(stream racing.Racing_ImportRacesServer) error {
+	ctx := stream.Context()
+
+	var (
+		provider string
+		dryRun   bool
+		rows     []*racing.ImportRaceRow
+	)
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if first {
+			provider, dryRun = req.Provider, req.DryRun
+		} else if req.Provider != "" && req.Provider != provider {
+			return apierr.InvalidArgument(apierr.FieldViolation{Field: "provider", Description: "must not change during an import"})
+		}
+		if len(rows)+len(req.Rows) > maxImportRows {
+			return apierr.InvalidArgument(apierr.FieldViolation{Field: "rows", Description: fmt.Sprintf("an import may hold at most %d rows", maxImportRows)})
+		}
+
+		for _, row := range req.Rows {
+			if row.Row == 0 {
+				row.Row = int64(len(rows) + 1)
+			}
+			rows = append(rows, row)
+		}
+	}
+	if provider == "" {
+		return apierr.InvalidArgument(apierr.FieldViolation{Field: "provider", Description: "is required"})
+	}
+
+	if errs := validateImportRows(rows); len(errs) > 0 {
+		return stream.SendAndClose(&racing.ImportRacesResponse{
+			Errors:  errs,
+			Summary: &racing.ImportSummary{Rows: int64(len(rows))},
+		})
+	}
+
+	if !dryRun {
+		defer s.racesChanged()
+	}
+
+	resp, err := s.racesRepo.Import(ctx, provider, rows, dryRun)
+	if err != nil {
+		return repoError(ctx, "import races", err)
+	}
+	resp.Applied = !dryRun
+
+	return stream.SendAndClose(resp)
+}
+
+// validateImportRows checks each row on its own, then that the rows agree with
+// one another: each race appears once, and a meeting has the same name in
+// every row naming it.
+func validateImportRows(rows []*racing.ImportRaceRow) []*racing.ImportRowError {
+	var (
+		errs     []*racing.ImportRowError
+		races    = make(map[string]*racing.ImportRaceRow)
+		meetings = make(map[string]*racing.ImportRaceRow)
+	)
+	add := func(row *racing.ImportRaceRow, field, description string) {
+		errs = append(errs, &racing.ImportRowError{Row: row.Row, Field: field, Description: description})
+	}
+	text := func(row *racing.ImportRaceRow, field, value string, maxLen int) {
+		switch {
+		case value == "":
+			add(row, field, "is required")
+		case utf8.RuneCountInString(value) > maxLen:
+			add(row, field, fmt.Sprintf("must be at most %d characters", maxLen))
+		}
+	}
+
+	for _, row := range rows {
+		text(row, "meeting_external_id", row.MeetingExternalId, maxImportIDLen)
+		text(row, "meeting_name", row.MeetingName, maxImportNameLen)
+		text(row, "external_id", row.ExternalId, maxImportIDLen)
+		text(row, "name", row.Name, maxImportNameLen)
+		if row.Number < 1 {
+			add(row, "number", "must be at least 1")
+		}
+		if row.AdvertisedStartTime == nil {
+			add(row, "advertised_start_time", "is required")
+		} else if err := row.AdvertisedStartTime.CheckValid(); err != nil {
+			add(row, "advertised_start_time", "must be a valid time")
+		}
+
+		if row.ExternalId != "" {
+			if first, ok := races[row.ExternalId]; ok {
+				add(row, "external_id", fmt.Sprintf("race %q is already on row %d", row.ExternalId, first.Row))
+			} else {
+				races[row.ExternalId] = row
+			}
+		}
+		if row.MeetingExternalId != "" {
+			if first, ok := meetings[row.MeetingExternalId]; !ok {
+				meetings[row.MeetingExternalId] = row
+			} else if row.MeetingName != first.MeetingName {
+				add(row, "meeting_name", fmt.Sprintf("meeting %q is named %q on row %d", row.MeetingExternalId, first.MeetingName, first.Row))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"git.neds.sh/matty/entain/racing/db"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// importRepo is a RacesRepo that records the import it is asked to apply.
+type importRepo struct {
+	db.RacesRepo
+	provider string
+	rows     []*racing.ImportRaceRow
+	dryRun   bool
+	imports  int
+}
+
+func (r *importRepo) Import(_ context.Context, provider string, rows []*racing.ImportRaceRow, dryRun bool) (*racing.ImportRacesResponse, error) {
+	r.provider, r.rows, r.dryRun = provider, rows, dryRun
+	r.imports++
+
+	return &racing.ImportRacesResponse{
+		Changes: []*racing.ImportChange{{Row: rows[0].Row, Action: racing.ImportAction_IMPORT_ACTION_CREATE, Kind: "race", Id: 1, ExternalId: rows[0].ExternalId}},
+		Summary: &racing.ImportSummary{Rows: int64(len(rows)), RacesCreated: int64(len(rows))},
+	}, nil
+}
+
+// importStream is the server side of an ImportRaces stream that receives reqs.
+type importStream struct {
+	grpc.ClientStreamingServer[racing.ImportRacesRequest, racing.ImportRacesResponse]
+	reqs []*racing.ImportRacesRequest
+	resp *racing.ImportRacesResponse
+}
+
+func (s *importStream) Context() context.Context { return context.Background() }
+
+func (s *importStream) Recv() (*racing.ImportRacesRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *importStream) SendAndClose(resp *racing.ImportRacesResponse) error {
+	s.resp = resp
+	return nil
+}
+
+// importRow returns a valid row for race externalID in meeting M1.
+func importRow(externalID string) *racing.ImportRaceRow {
+	return &racing.ImportRaceRow{
+		MeetingExternalId:   "M1",
+		MeetingName:         "Flemington",
+		ExternalId:          externalID,
+		Name:                "Race " + externalID,
+		Number:              1,
+		Visible:             true,
+		AdvertisedStartTime: timestamppb.New(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestImportRaces(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		repo := &importRepo{}
+		s := NewRacingService(repo)
+
+		stream := &importStream{reqs: []*racing.ImportRacesRequest{
+			{Provider: "tab", DryRun: dryRun, Rows: []*racing.ImportRaceRow{importRow("R1"), importRow("R2")}},
+			{Rows: []*racing.ImportRaceRow{importRow("R3")}},
+			{Provider: "tab"},
+		}}
+		require.NoError(t, s.ImportRaces(stream))
+
+		assert.Equal(t, "tab", repo.provider)
+		assert.Equal(t, dryRun, repo.dryRun)
+		if assert.Len(t, repo.rows, 3) {
+			for i, row := range repo.rows {
+				assert.EqualValues(t, i+1, row.Row, "rows without a number are numbered in stream order")
+			}
+		}
+
+		assert.Equal(t, !dryRun, stream.resp.Applied)
+		assert.Empty(t, stream.resp.Errors)
+		assert.Len(t, stream.resp.Changes, 1)
+		assert.EqualValues(t, 3, stream.resp.Summary.RacesCreated)
+	}
+}
+
+func TestImportRaces_RowErrors(t *testing.T) {
+	repo := &importRepo{}
+	s := NewRacingService(repo)
+
+	missing := &racing.ImportRaceRow{Row: 2}
+	long := importRow("R3")
+	long.Row, long.Name = 3, strings.Repeat("a", maxImportNameLen+1)
+	duplicate := importRow("R1")
+	duplicate.Row = 4
+	renamed := importRow("R5")
+	renamed.Row, renamed.MeetingName = 5, "Flemington Park"
+	badTime := importRow("R6")
+	badTime.Row, badTime.AdvertisedStartTime = 6, &timestamppb.Timestamp{Seconds: -1 << 62}
+
+	first := importRow("R1")
+	first.Row = 1
+	stream := &importStream{reqs: []*racing.ImportRacesRequest{{
+		Provider: "tab",
+		Rows:     []*racing.ImportRaceRow{first, missing, long, duplicate, renamed, badTime},
+	}}}
+	require.NoError(t, s.ImportRaces(stream))
+
+	assert.Zero(t, repo.imports, "nothing is imported when any row is invalid")
+	assert.False(t, stream.resp.Applied)
+	assert.EqualValues(t, 6, stream.resp.Summary.Rows)
+
+	type rowField struct {
+		row   int64
+		field string
+	}
+	var got []rowField
+	for _, e := range stream.resp.Errors {
+		got = append(got, rowField{e.Row, e.Field})
+	}
+	assert.Equal(t, []rowField{
+		{2, "meeting_external_id"},
+		{2, "meeting_name"},
+		{2, "external_id"},
+		{2, "name"},
+		{2, "number"},
+		{2, "advertised_start_time"},
+		{3, "name"},
+		{4, "external_id"},
+		{5, "meeting_name"},
+		{6, "advertised_start_time"},
+	}, got)
+}
+
+func TestImportRaces_StreamErrors(t *testing.T) {
+	tooMany := make([]*racing.ImportRaceRow, maxImportRows+1)
+	for i := range tooMany {
+		tooMany[i] = importRow("R")
+	}
+
+	tests := []struct {
+		name string
+		reqs []*racing.ImportRacesRequest
+	}{
+		{name: "empty stream"},
+		{name: "no provider", reqs: []*racing.ImportRacesRequest{{Rows: []*racing.ImportRaceRow{importRow("R1")}}}},
+		{name: "provider changes", reqs: []*racing.ImportRacesRequest{{Provider: "tab"}, {Provider: "ubet"}}},
+		{name: "too many rows", reqs: []*racing.ImportRacesRequest{{Provider: "tab", Rows: tooMany}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &importRepo{}
+			err := NewRacingService(repo).ImportRaces(&importStream{reqs: tt.reqs})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Zero(t, repo.imports)
+		})
+	}
+}
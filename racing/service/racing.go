@@ -1,56 +1,359 @@
 package service
 
 import (
+	"bytes"
 	"database/sql"
-	"fmt"
-	"strings"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
 
 	"git.neds.sh/matty/entain/racing/db"
-	"git.neds.sh/matty/entain/racing/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // racingService implements the Racing interface.
 type racingService struct {
 	racing.UnimplementedRacingServer // Embedding due to later version of Go
 	racesRepo                        db.RacesRepo
+	listCache                        *listCache
+}
+
+// Option configures a racingService.
+type Option func(*racingService)
+
+// WithListCache caches ListRaces results for up to ttl. Any race write
+// invalidates the cache. A ttl of zero leaves ListRaces uncached.
+func WithListCache(ttl time.Duration) Option {
+	return func(s *racingService) {
+		if ttl > 0 {
+			s.listCache = newListCache(ttl)
+		}
+	}
 }
 
 // NewRacingService instantiates and returns a new racingService.
-func NewRacingService(racesRepo db.RacesRepo) racing.RacingServer {
-	return &racingService{racesRepo: racesRepo}
+func NewRacingService(racesRepo db.RacesRepo, opts ...Option) racing.RacingServer {
+	s := &racingService{racesRepo: racesRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *racingService) ListRaces(ctx context.Context, in *racing.ListRacesRequest) (*racing.ListRacesResponse, error) {
-	// fallback values in case the Sort field is nil
-	field := "advertised_start_time"
-	direction := "ASC"
+	if in.Sort != nil && len(in.Sorts) > 0 {
+		return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: "sort", Description: "set either sort or sorts, not both"})
+	}
+
+	// Older clients send a single sort, where an empty field meant the default.
+	// The fields and directions themselves are checked against the rules in
+	// racing.proto before we get here.
+	sorts, sortPath := in.Sorts, "sorts"
 	if in.Sort != nil {
-		if in.Sort.Field != "" {
-			field = in.Sort.Field
+		field := in.Sort.Field
+		if field == "" {
+			field = "advertised_start_time"
 		}
-		if strings.ToUpper(in.Sort.Direction) == "DESC" {
-			direction = "DESC"
+		sorts, sortPath = []*racing.Sort{{Field: field, Direction: in.Sort.Direction}}, "sort"
+	}
+
+	fields, err := db.ParseReadMask(in.ReadMask)
+	if err != nil {
+		return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: "read_mask", Description: err.Error()})
+	}
+
+	after, ok := parsePageToken(in.PageToken)
+	if !ok {
+		return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: "page_token", Description: "is not a token from next_page_token"})
+	}
+	page := db.Page{After: after}
+	if in.PageSize > 0 {
+		// One more than asked for, to tell whether there is another page.
+		page.Limit = int(in.PageSize) + 1
+	}
+
+	// The next page token is made from the last race, so read its key whatever
+	// the mask.
+	key := db.PageKey(sorts)
+	read := fields
+	for field := range key {
+		read = read.With(field)
+	}
+
+	races, err := s.listRaces(ctx, in.Filter, sorts, read, page)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidSortField) || errors.Is(err, db.ErrInvalidSortDirection) {
+			return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: sortPath, Description: err.Error()})
 		}
+		return nil, repoError(ctx, "list races", err)
 	}
 
-	races, err := s.racesRepo.List(in.Filter, field, direction)
+	resp := &racing.ListRacesResponse{Races: races}
+	if in.PageSize > 0 && len(races) > int(in.PageSize) {
+		resp.Races = races[:in.PageSize]
+		resp.NextPageToken = pageToken(resp.Races[len(resp.Races)-1], key)
+	}
+	for _, race := range resp.Races {
+		fields.Apply(race)
+	}
+	if in.IncludeFacets {
+		// Counted by the database each time, rather than from the races in the
+		// list cache.
+		resp.Facets, err = s.racesRepo.Facets(ctx, in.Filter)
+		if err != nil {
+			return nil, repoError(ctx, "count races", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// listRaces lists the given fields of the races in page through the list
+// cache, if there is one. The cache holds whole lists of whole races, whatever
+// page and fields were asked for, so that every page and read mask shares its
+// entries.
+func (s *racingService) listRaces(ctx context.Context, filter *racing.ListRacesRequestFilter, sorts []*racing.Sort, fields db.RaceFields, page db.Page) ([]*racing.Race, error) {
+	if s.listCache == nil {
+		return s.racesRepo.List(ctx, filter, sorts, fields, page)
+	}
+
+	races, err := s.listCache.list(ctx, filter, sorts, func(ctx context.Context) ([]*racing.Race, error) {
+		return s.racesRepo.List(ctx, filter, sorts, nil, db.Page{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	races, err = page.Slice(races, sorts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list races: %v", err)
+		return nil, err
+	}
+	for _, race := range races {
+		fields.Apply(race)
 	}
+	return races, nil
+}
+
+// pageTokenPrefix starts every decoded page token, so a token from elsewhere
+// is not mistaken for one.
+const pageTokenPrefix = "after:"
 
-	return &racing.ListRacesResponse{Races: races}, nil
+// pageToken returns the token for the page of a list that follows race. The
+// token holds only race's fields in key, those that place it in the list.
+func pageToken(race *racing.Race, key db.RaceFields) string {
+	race = proto.Clone(race).(*racing.Race)
+	key.Apply(race)
+	raw, _ := proto.Marshal(race)
+	return base64.RawURLEncoding.EncodeToString(append([]byte(pageTokenPrefix), raw...))
+}
+
+// parsePageToken returns the race token's page starts after, and reports
+// whether token came from pageToken. An empty token starts at the beginning,
+// after no race.
+func parsePageToken(token string) (*racing.Race, bool) {
+	if token == "" {
+		return nil, true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false
+	}
+	raw, ok := bytes.CutPrefix(raw, []byte(pageTokenPrefix))
+	var race racing.Race
+	if !ok || proto.Unmarshal(raw, &race) != nil || race.Id < 1 {
+		return nil, false
+	}
+	return &race, true
 }
 
 func (s *racingService) GetRace(ctx context.Context, req *racing.GetRaceRequest) (*racing.GetRaceResponse, error) {
-	race, err := s.racesRepo.GetByID(req.Id)
+	fields, err := db.ParseReadMask(req.ReadMask)
+	if err != nil {
+		return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: "read_mask", Description: err.Error()})
+	}
+
+	// Whatever the mask, visibility decides who may see the race, and the start
+	// time stands in for the original one when there is no history.
+	read := fields.With("visible")
+	if req.IncludeHistory {
+		read = read.With("advertised_start_time")
+	}
+
+	race, err := s.racesRepo.GetByID(ctx, req.Id, read)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "race %d not found", req.Id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apierr.NotFound("race", strconv.FormatInt(req.Id, 10))
 		}
-		return nil, status.Errorf(codes.Internal, "error fetching race: %v", err)
+		return nil, repoError(ctx, "get race", err)
+	}
+
+	// Hidden races do not exist as far as the public is concerned.
+	if !race.Visible && !auth.FromContext(ctx).HasAnyRole(RoleInternal) {
+		return nil, apierr.NotFound("race", strconv.FormatInt(req.Id, 10))
+	}
+
+	resp := &racing.GetRaceResponse{Race: race}
+	if req.IncludeHistory {
+		history, err := s.racesRepo.ScheduleHistory(ctx, req.Id)
+		if err != nil {
+			return nil, repoError(ctx, "get race history", err)
+		}
+		resp.History = history
+
+		// The first change records the start time the race was advertised with
+		// before anything moved it.
+		resp.OriginalStartTime = race.AdvertisedStartTime
+		if len(history) > 0 {
+			resp.OriginalStartTime = history[0].PreviousStartTime
+		}
+	}
+	fields.Apply(race)
+
+	return resp, nil
+}
+
+func (s *racingService) BatchGetRaces(ctx context.Context, req *racing.BatchGetRacesRequest) (*racing.BatchGetRacesResponse, error) {
+	fields, err := db.ParseReadMask(req.ReadMask)
+	if err != nil {
+		return nil, apierr.InvalidArgument(apierr.FieldViolation{Field: "read_mask", Description: err.Error()})
+	}
+
+	// Asking for a race twice returns it once.
+	ids := make([]int64, 0, len(req.Ids))
+	seen := make(map[int64]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// The id matches races to the ids asked for, and visibility decides who
+	// may see them, whatever the mask.
+	races, err := s.racesRepo.GetByIDs(ctx, ids, fields.With("id").With("visible"))
+	if err != nil {
+		return nil, repoError(ctx, "batch get races", err)
+	}
+
+	// As with GetRace, hidden races are not found by the public.
+	showHidden := auth.FromContext(ctx).HasAnyRole(RoleInternal)
+	byID := make(map[int64]*racing.Race, len(races))
+	for _, race := range races {
+		if race.Visible || showHidden {
+			byID[race.Id] = race
+		}
+	}
+
+	resp := &racing.BatchGetRacesResponse{}
+	for _, id := range ids {
+		race, ok := byID[id]
+		if !ok {
+			resp.NotFound = append(resp.NotFound, id)
+			continue
+		}
+		fields.Apply(race)
+		resp.Races = append(resp.Races, race)
+	}
+
+	return resp, nil
+}
+
+func (s *racingService) DelayRace(ctx context.Context, req *racing.DelayRaceRequest) (*racing.DelayRaceResponse, error) {
+	defer s.racesChanged()
+
+	race, err := s.racesRepo.Delay(ctx, req.Id, req.NewStartTime.AsTime(), req.Reason)
+	if err != nil {
+		return nil, scheduleError(ctx, req.Id, err)
+	}
+	return &racing.DelayRaceResponse{Race: race}, nil
+}
+
+func (s *racingService) AbandonRace(ctx context.Context, req *racing.AbandonRaceRequest) (*racing.AbandonRaceResponse, error) {
+	defer s.racesChanged()
+
+	race, err := s.racesRepo.Abandon(ctx, req.Id, req.Reason)
+	if err != nil {
+		return nil, scheduleError(ctx, req.Id, err)
+	}
+	return &racing.AbandonRaceResponse{Race: race}, nil
+}
+
+func (s *racingService) ReinstateRace(ctx context.Context, req *racing.ReinstateRaceRequest) (*racing.ReinstateRaceResponse, error) {
+	defer s.racesChanged()
+
+	var newStart *time.Time
+	if req.NewStartTime != nil {
+		t := req.NewStartTime.AsTime()
+		newStart = &t
+	}
+
+	race, err := s.racesRepo.Reinstate(ctx, req.Id, newStart, req.Reason)
+	if err != nil {
+		return nil, scheduleError(ctx, req.Id, err)
+	}
+	return &racing.ReinstateRaceResponse{Race: race}, nil
+}
+
+// racesChanged invalidates the list cache after a write. It runs whether or not
+// the write succeeded, since a write that failed late may still have committed.
+func (s *racingService) racesChanged() {
+	if s.listCache != nil {
+		s.listCache.invalidate()
+	}
+}
+
+// defaultSearchLimit is the number of search results returned when the request
+// does not set a limit.
+const defaultSearchLimit = 10
+
+func (s *racingService) Search(ctx context.Context, req *racing.SearchRequest) (*racing.SearchResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	results, err := s.racesRepo.Search(ctx, req.Query, limit)
+	if errors.Is(err, fts.ErrUnavailable) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return nil, repoError(ctx, "search races", err)
+	}
+
+	return &racing.SearchResponse{Results: results}, nil
+}
+
+// scheduleError maps a repository error from a schedule change onto a gRPC status.
+func scheduleError(ctx context.Context, id int64, err error) error {
+	subject := "race " + strconv.FormatInt(id, 10)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return apierr.NotFound("race", strconv.FormatInt(id, 10))
+	case errors.Is(err, db.ErrDelayNotLater):
+		return apierr.InvalidArgument(apierr.FieldViolation{Field: "new_start_time", Description: err.Error()})
+	case errors.Is(err, db.ErrRaceAbandoned):
+		return apierr.FailedPrecondition("ABANDONED", subject, err.Error())
+	case errors.Is(err, db.ErrRaceNotAbandoned):
+		return apierr.FailedPrecondition("NOT_ABANDONED", subject, err.Error())
+	default:
+		return repoError(ctx, "change race schedule", err)
+	}
+}
+
+// repoError maps a repository error with no more specific meaning onto a gRPC
+// status: Canceled or DeadlineExceeded when the caller gave up or the query
+// ran out of time, and Internal otherwise.
+func repoError(ctx context.Context, op string, err error) error {
+	if err := apierr.FromContext(op, err); err != nil {
+		return err
 	}
-	return &racing.GetRaceResponse{Race: race}, nil
+	return apierr.Internal(ctx, op, err)
 }
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"testing"
+	"time"
+
+	"git.neds.sh/matty/entain/racing/db"
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maskRepo is a RacesRepo holding one race, which records the fields it was
+// asked to read.
+type maskRepo struct {
+	db.RacesRepo
+	race   *racing.Race
+	fields db.RaceFields
+	facets int
+}
+
+func (r *maskRepo) List(_ context.Context, _ *racing.ListRacesRequestFilter, _ []*racing.Sort, fields db.RaceFields, _ db.Page) ([]*racing.Race, error) {
+	r.fields = fields
+	race := &racing.Race{Id: r.race.Id, Name: r.race.Name, Visible: r.race.Visible, AdvertisedStartTime: r.race.AdvertisedStartTime}
+	fields.Apply(race)
+	return []*racing.Race{race}, nil
+}
+
+func (r *maskRepo) GetByID(_ context.Context, _ int64, fields db.RaceFields) (*racing.Race, error) {
+	r.fields = fields
+	race := &racing.Race{Id: r.race.Id, Name: r.race.Name, Visible: r.race.Visible, AdvertisedStartTime: r.race.AdvertisedStartTime}
+	fields.Apply(race)
+	return race, nil
+}
+
+func (r *maskRepo) Facets(_ context.Context, filter *racing.ListRacesRequestFilter) (*racing.RaceFacets, error) {
+	r.facets++
+	return &racing.RaceFacets{TotalCount: 1, Meetings: []*racing.MeetingCount{{MeetingId: filter.GetMeetingIds()[0], Count: 1}}}, nil
+}
+
+func (r *maskRepo) ScheduleHistory(context.Context, int64) ([]*racing.ScheduleChange, error) {
+	return nil, nil
+}
+
+func TestListRaces_ReadMask(t *testing.T) {
+	start := timestamppb.New(time.Now().Add(time.Hour))
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"id", "name"}}
+
+	for _, opts := range [][]Option{nil, {WithListCache(time.Minute)}} {
+		repo := &maskRepo{race: &racing.Race{Id: 1, Name: "Cup", Visible: true, AdvertisedStartTime: start}}
+		s := NewRacingService(repo, opts...)
+
+		resp, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{ReadMask: mask})
+		if assert.NoError(t, err) && assert.Len(t, resp.Races, 1) {
+			assert.Equal(t, int64(1), resp.Races[0].Id)
+			assert.Equal(t, "Cup", resp.Races[0].Name)
+			assert.False(t, resp.Races[0].Visible)
+			assert.Nil(t, resp.Races[0].AdvertisedStartTime)
+		}
+	}
+
+	s := NewRacingService(&maskRepo{race: &racing.Race{Id: 1}})
+	_, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"runners"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, []string{"read_mask"}, violatedFields(err))
+}
+
+func TestListRaces_Facets(t *testing.T) {
+	repo := &maskRepo{race: &racing.Race{Id: 1, Visible: true}}
+	s := NewRacingService(repo, WithListCache(time.Minute))
+	filter := &racing.ListRacesRequestFilter{MeetingIds: []int64{7}}
+
+	resp, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{Filter: filter})
+	if assert.NoError(t, err) {
+		assert.Nil(t, resp.Facets, "only when asked for")
+	}
+
+	for i := 1; i <= 2; i++ {
+		resp, err = s.ListRaces(context.Background(), &racing.ListRacesRequest{Filter: filter, IncludeFacets: true})
+		if assert.NoError(t, err) && assert.NotNil(t, resp.Facets) {
+			assert.Len(t, resp.Races, 1)
+			assert.EqualValues(t, 1, resp.Facets.TotalCount)
+			assert.Equal(t, int64(7), resp.Facets.Meetings[0].MeetingId, "counted with the request's filter")
+		}
+		assert.Equal(t, i, repo.facets, "counted every time, not cached")
+	}
+}
+
+func TestGetRace_ReadMask(t *testing.T) {
+	start := timestamppb.New(time.Now().Add(time.Hour))
+	repo := &maskRepo{race: &racing.Race{Id: 1, Name: "Cup", Visible: true, AdvertisedStartTime: start}}
+	s := NewRacingService(repo)
+	ctx := context.Background()
+
+	resp, err := s.GetRace(ctx, &racing.GetRaceRequest{Id: 1, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}, IncludeHistory: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, db.RaceFields{"name": true, "visible": true, "advertised_start_time": true}, repo.fields, "visibility and the original start time are always read")
+		assert.Equal(t, "Cup", resp.Race.Name)
+		assert.False(t, resp.Race.Visible)
+		assert.Nil(t, resp.Race.AdvertisedStartTime)
+		assert.Equal(t, start.AsTime(), resp.OriginalStartTime.AsTime())
+	}
+
+	// A hidden race stays hidden, whatever the mask leaves out.
+	repo.race.Visible = false
+	_, err = s.GetRace(ctx, &racing.GetRaceRequest{Id: 1, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetRace(ctx, &racing.GetRaceRequest{Id: 1, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "runners"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// violatedFields returns the fields named by an InvalidArgument error's
+// BadRequest details.
+func violatedFields(err error) []string {
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.GetFieldViolations() {
+				fields = append(fields, v.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+// batchRepo is a RacesRepo holding races by id.
+type batchRepo struct {
+	db.RacesRepo
+	races  map[int64]*racing.Race
+	ids    []int64
+	fields db.RaceFields
+}
+
+func (r *batchRepo) GetByIDs(_ context.Context, ids []int64, fields db.RaceFields) ([]*racing.Race, error) {
+	r.ids, r.fields = ids, fields
+	var races []*racing.Race
+	// Found races come back in id order, not the order asked for.
+	for id := int64(1); id <= 10; id++ {
+		if race, ok := r.races[id]; ok && slices.Contains(ids, id) {
+			races = append(races, &racing.Race{Id: race.Id, Name: race.Name, Visible: race.Visible})
+		}
+	}
+	return races, nil
+}
+
+func TestBatchGetRaces(t *testing.T) {
+	repo := &batchRepo{races: map[int64]*racing.Race{
+		1: {Id: 1, Name: "One", Visible: true},
+		2: {Id: 2, Name: "Two", Visible: true},
+		3: {Id: 3, Name: "Hidden"},
+	}}
+	s := NewRacingService(repo)
+
+	resp, err := s.BatchGetRaces(context.Background(), &racing.BatchGetRacesRequest{Ids: []int64{2, 9, 1, 2, 3}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int64{2, 9, 1, 3}, repo.ids, "one query, each id once")
+
+		var names []string
+		for _, race := range resp.Races {
+			names = append(names, race.Name)
+		}
+		assert.Equal(t, []string{"Two", "One"}, names, "in the order asked for")
+		assert.Equal(t, []int64{9, 3}, resp.NotFound, "hidden races are not found by the public")
+	}
+
+	internal := auth.NewContext(context.Background(), &auth.Caller{Roles: []string{RoleInternal}})
+	resp, err = s.BatchGetRaces(internal, &racing.BatchGetRacesRequest{Ids: []int64{3}, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}})
+	if assert.NoError(t, err) && assert.Len(t, resp.Races, 1) {
+		assert.Equal(t, db.RaceFields{"name": true, "id": true, "visible": true}, repo.fields)
+		assert.Equal(t, "Hidden", resp.Races[0].Name)
+		assert.Zero(t, resp.Races[0].Id, "only the fields asked for")
+		assert.Empty(t, resp.NotFound)
+	}
+
+	_, err = s.BatchGetRaces(context.Background(), &racing.BatchGetRacesRequest{Ids: []int64{1}, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"runners"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// listRepo is a RacesRepo listing a fixed set of races, in page.
+type listRepo struct {
+	db.RacesRepo
+	races []*racing.Race
+	pages []db.Page
+}
+
+func (r *listRepo) List(_ context.Context, _ *racing.ListRacesRequestFilter, sorts []*racing.Sort, _ db.RaceFields, page db.Page) ([]*racing.Race, error) {
+	r.pages = append(r.pages, page)
+	return page.Slice(r.races, sorts)
+}
+
+func TestListRaces_Pages(t *testing.T) {
+	for _, cached := range []bool{false, true} {
+		repo := &listRepo{}
+		for id := int64(1); id <= 5; id++ {
+			repo.races = append(repo.races, &racing.Race{Id: id, AdvertisedStartTime: timestamppb.New(time.Now().Add(time.Hour))})
+		}
+		var opts []Option
+		if cached {
+			opts = append(opts, WithListCache(time.Minute))
+		}
+		s := NewRacingService(repo, opts...)
+
+		var (
+			got   []int64
+			token string
+			pages int
+		)
+		for {
+			resp, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{PageSize: 2, PageToken: token})
+			if !assert.NoError(t, err) {
+				return
+			}
+			pages++
+			for _, race := range resp.Races {
+				got = append(got, race.Id)
+			}
+			if pages == 1 {
+				// A race sorted before the first page, added after it was
+				// read, does not shift the later pages.
+				repo.races = append([]*racing.Race{{Id: 6, AdvertisedStartTime: timestamppb.New(time.Now())}}, repo.races...)
+			}
+			if token = resp.NextPageToken; token == "" {
+				break
+			}
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, got, "cached: %v", cached)
+		repo.races = repo.races[1:]
+		assert.Equal(t, 3, pages)
+		if cached {
+			assert.Equal(t, []db.Page{{}}, repo.pages, "pages are cut from one cached list")
+		} else {
+			last := repo.pages[len(repo.pages)-1]
+			assert.Equal(t, int64(4), last.After.GetId(), "the page starts after the last race of the one before")
+			assert.Equal(t, 3, last.Limit, "one more than a page is asked for")
+		}
+
+		// Without a page size every race is listed, and there is no next page.
+		resp, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{})
+		if assert.NoError(t, err) {
+			assert.Len(t, resp.Races, 5)
+			assert.Empty(t, resp.NextPageToken)
+		}
+	}
+
+	// The token holds the last race's key, whatever the read mask leaves out.
+	repo := &listRepo{races: []*racing.Race{{Id: 1, Name: "Alpha"}, {Id: 2, Name: "Bravo"}, {Id: 3, Name: "Charlie"}}}
+	s := NewRacingService(repo)
+	req := &racing.ListRacesRequest{Sorts: []*racing.Sort{{Field: "name"}}, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"number"}}, PageSize: 2}
+	resp, err := s.ListRaces(context.Background(), req)
+	if assert.NoError(t, err) && assert.Len(t, resp.Races, 2) {
+		assert.Zero(t, resp.Races[1].Id)
+		assert.Empty(t, resp.Races[1].Name)
+
+		req.PageToken = resp.NextPageToken
+		_, err = s.ListRaces(context.Background(), req)
+		assert.NoError(t, err)
+		last := repo.pages[len(repo.pages)-1]
+		assert.Equal(t, int64(2), last.After.GetId())
+		assert.Equal(t, "Bravo", last.After.GetName())
+		assert.Nil(t, last.After.GetAdvertisedStartTime(), "the token holds only the fields sorted by")
+	}
+
+	s = NewRacingService(&listRepo{})
+	for _, token := range []string{"not base64!", "b2Zmc2V0Oi0x", base64.RawURLEncoding.EncodeToString([]byte("page:2"))} {
+		_, err := s.ListRaces(context.Background(), &racing.ListRacesRequest{PageToken: token})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), token)
+	}
+}
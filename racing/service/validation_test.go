@@ -0,0 +1,169 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/proto/racing"
+	"github.com/SylvanSol/Entain_Test/common/validate"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestRequestValidation checks the rules declared in racing.proto.
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        proto.Message
+		wantFields []string
+	}{
+		{
+			name: "empty list request",
+			req:  &racing.ListRacesRequest{},
+		},
+		{
+			name: "valid filter and sort",
+			req: &racing.ListRacesRequest{
+				Filter: &racing.ListRacesRequestFilter{MeetingIds: []int64{1, 2}, OnlyVisible: true},
+				Sort:   &racing.Sort{Field: "name", Direction: "DESC"},
+			},
+		},
+		{
+			name: "negative meeting id",
+			req: &racing.ListRacesRequest{
+				Filter: &racing.ListRacesRequestFilter{MeetingIds: []int64{1, -3}},
+			},
+			wantFields: []string{"filter.meeting_ids[1]"},
+		},
+		{
+			name: "too many meeting ids",
+			req: &racing.ListRacesRequest{
+				Filter: &racing.ListRacesRequestFilter{MeetingIds: meetingIDs(101)},
+			},
+			wantFields: []string{"filter.meeting_ids"},
+		},
+		{
+			name:       "unknown sort field and direction",
+			req:        &racing.ListRacesRequest{Sort: &racing.Sort{Field: "colour", Direction: "up"}},
+			wantFields: []string{"sort.field", "sort.direction"},
+		},
+		{
+			name: "multi-field sort",
+			req: &racing.ListRacesRequest{Sorts: []*racing.Sort{
+				{Field: "meeting_id"}, {Field: "number", Direction: "asc"},
+			}},
+		},
+		{
+			name: "unknown field in sorts",
+			req: &racing.ListRacesRequest{Sorts: []*racing.Sort{
+				{Field: "meeting_id"}, {Field: "colour"},
+			}},
+			wantFields: []string{"sorts[1].field"},
+		},
+		{
+			name:       "page too large",
+			req:        &racing.ListRacesRequest{PageSize: 1001},
+			wantFields: []string{"page_size"},
+		},
+		{
+			name:       "negative page size",
+			req:        &racing.ListRacesRequest{PageSize: -1},
+			wantFields: []string{"page_size"},
+		},
+		{
+			name:       "empty race id",
+			req:        &racing.GetRaceRequest{},
+			wantFields: []string{"id"},
+		},
+		{
+			name:       "empty batch",
+			req:        &racing.BatchGetRacesRequest{},
+			wantFields: []string{"ids"},
+		},
+		{
+			name:       "batch too large with a bad id",
+			req:        &racing.BatchGetRacesRequest{Ids: append(meetingIDs(100), 0)},
+			wantFields: []string{"ids", "ids[100]"},
+		},
+		{
+			name: "valid batch",
+			req:  &racing.BatchGetRacesRequest{Ids: []int64{3, 1, 3}},
+		},
+		{
+			name:       "delay without start time or reason",
+			req:        &racing.DelayRaceRequest{Id: 1},
+			wantFields: []string{"new_start_time", "reason"},
+		},
+		{
+			name: "valid delay",
+			req:  &racing.DelayRaceRequest{Id: 1, NewStartTime: timestamppb.Now(), Reason: "track inspection"},
+		},
+		{
+			name:       "abandon without reason",
+			req:        &racing.AbandonRaceRequest{Id: 1},
+			wantFields: []string{"reason"},
+		},
+		{
+			name:       "abandon with a blank reason",
+			req:        &racing.AbandonRaceRequest{Id: 1, Reason: "   "},
+			wantFields: []string{"reason"},
+		},
+		{
+			name:       "empty search",
+			req:        &racing.SearchRequest{},
+			wantFields: []string{"query"},
+		},
+		{
+			name:       "search limit too high",
+			req:        &racing.SearchRequest{Query: "red", Limit: 51},
+			wantFields: []string{"limit"},
+		},
+		{
+			name:       "reinstate without reason",
+			req:        &racing.ReinstateRaceRequest{Id: 1},
+			wantFields: []string{"reason"},
+		},
+		{
+			name: "valid reinstate",
+			req:  &racing.ReinstateRaceRequest{Id: 1, Reason: "rain cleared"},
+		},
+		{
+			name: "import message without provider",
+			req:  &racing.ImportRacesRequest{Rows: importRows(1000)},
+		},
+		{
+			name:       "import message too large",
+			req:        &racing.ImportRacesRequest{Provider: strings.Repeat("p", 51), Rows: importRows(1001)},
+			wantFields: []string{"provider", "rows"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, v := range validate.Message(tt.req) {
+				fields = append(fields, v.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+// meetingIDs returns n distinct, valid meeting ids.
+func meetingIDs(n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+// importRows returns n empty import rows.
+func importRows(n int) []*racing.ImportRaceRow {
+	rows := make([]*racing.ImportRaceRow, n)
+	for i := range rows {
+		rows[i] = &racing.ImportRaceRow{}
+	}
+	return rows
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+)
+
+// Config is the sports service's configuration. It is loaded from, in
+// increasing order of precedence, defaultConfig, the YAML file named by -config
+// or SPORTS_CONFIG, SPORTS_* environment variables and flags.
+type Config struct {
+	Listen    string           `yaml:"listen" flag:"grpc-endpoint" usage:"gRPC server endpoint"`
+	DB        config.DB        `yaml:"db"`
+	TLS       config.TLS       `yaml:"tls"`
+	Auth      config.Auth      `yaml:"auth"`
+	RateLimit config.RateLimit `yaml:"ratelimit"`
+	// TrustedProxies lists the CIDR prefixes, such as the api gateway's, whose
+	// x-forwarded-for metadata names the real client.
+	TrustedProxies string          `yaml:"trusted_proxies" flag:"trusted-proxies" usage:"comma-separated CIDR prefixes whose x-forwarded-for is trusted"`
+	Metrics        config.Metrics  `yaml:"metrics"`
+	Tracing        config.Tracing  `yaml:"tracing"`
+	Logging        config.Logging  `yaml:"logging"`
+	Timeouts       config.Timeouts `yaml:"timeouts"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Listen: "localhost:9100",
+		DB: config.DB{
+			DSN:          ":memory:",
+			Seed:         config.SeedDummy,
+			QueryTimeout: 5 * time.Second,
+		},
+		RateLimit: config.RateLimit{
+			Rate:  50,
+			Burst: 100,
+		},
+		// The api gateway, when it runs alongside.
+		TrustedProxies: "127.0.0.1/32,::1/128",
+		Metrics: config.Metrics{
+			Listen: "localhost:9190",
+		},
+		Tracing: config.Tracing{
+			Exporter: tracing.ExporterNone,
+			Endpoint: "http://localhost:4317",
+		},
+		Logging: config.Logging{
+			Level: "info",
+		},
+		Timeouts: config.Timeouts{
+			Shutdown: 10 * time.Second,
+		},
+	}
+}
+
+func (c *Config) Validate() error {
+	if _, err := ratelimit.ParsePrefixes(c.TrustedProxies); err != nil {
+		return fmt.Errorf("trusted_proxies: %w", err)
+	}
+	return config.ValidateAddress("listen", c.Listen)
+}
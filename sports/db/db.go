@@ -0,0 +1,63 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/fts"
+)
+
+// seedEvent is one of the example matches loaded at startup.
+type seedEvent struct {
+	name         string
+	location     string
+	participants []string
+	startsIn     time.Duration
+}
+
+// seedEvents are the example matches the service has always returned. (I don't
+// follow sport so I got ChatGPT to tell me some)
+var seedEvents = []seedEvent{
+	{"Red Hawks vs Blue Titans", "Thunder Dome", []string{"Red Hawks", "Blue Titans"}, 2 * time.Hour},
+	{"Iron Bears vs Golden Foxes", "Victory Stadium", []string{"Iron Bears", "Golden Foxes"}, 4 * time.Hour},
+	{"Night Wolves vs Storm Kings", "Arena Eclipse", []string{"Night Wolves", "Storm Kings"}, 6 * time.Hour},
+}
+
+func (r *eventsRepo) seed() error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS events (id INTEGER PRIMARY KEY, name TEXT, location TEXT, participants TEXT, advertised_start_time DATETIME)`,
+	}
+	if fts.Enabled {
+		statements = append(statements,
+			`CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(name, location, participants, content='events', content_rowid='id', tokenize='unicode61')`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_after_insert AFTER INSERT ON events BEGIN INSERT INTO events_fts(rowid, name, location, participants) VALUES (new.id, new.name, new.location, new.participants); END`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_after_delete AFTER DELETE ON events BEGIN INSERT INTO events_fts(events_fts, rowid, name, location, participants) VALUES ('delete', old.id, old.name, old.location, old.participants); END`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_after_update AFTER UPDATE ON events BEGIN INSERT INTO events_fts(events_fts, rowid, name, location, participants) VALUES ('delete', old.id, old.name, old.location, old.participants); INSERT INTO events_fts(rowid, name, location, participants) VALUES (new.id, new.name, new.location, new.participants); END`,
+		)
+	}
+	for _, statement := range statements {
+		if _, err := r.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	if r.noSeed {
+		return nil
+	}
+
+	now := time.Now()
+	for i, event := range seedEvents {
+		if _, err := r.db.Exec(
+			`INSERT OR REPLACE INTO events(id, name, location, participants, advertised_start_time) VALUES (?,?,?,?,?)`,
+			i+1,
+			event.name,
+			event.location,
+			strings.Join(event.participants, participantSeparator),
+			now.Add(event.startsIn).UTC().Format(time.RFC3339),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
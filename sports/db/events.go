@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// participantSeparator joins an event's participants in the participants column.
+const participantSeparator = ", "
+
+// EventsRepo provides repository access to sports events.
+type EventsRepo interface {
+	// Init will initialise our events repository.
+	Init() error
+
+	// List will return every event, soonest first.
+	List(ctx context.Context) ([]*sports.Event, error)
+
+	// Search returns up to limit events whose name, location or participants
+	// match query, best match first.
+	Search(ctx context.Context, query string, limit int) ([]*sports.SearchResult, error)
+
+	// Ping reports whether the events table can be read.
+	Ping(ctx context.Context) error
+}
+
+type eventsRepo struct {
+	db           *sql.DB
+	init         sync.Once
+	noSeed       bool
+	observe      func(query string, d time.Duration)
+	queryTimeout time.Duration
+}
+
+// Option configures an events repository.
+type Option func(*eventsRepo)
+
+// WithoutDummyData makes Init create the schema without loading the example
+// events.
+func WithoutDummyData() Option {
+	return func(r *eventsRepo) { r.noSeed = true }
+}
+
+// WithQueryTimeout cancels any List or Search still running after d, failing it
+// with an error wrapping context.DeadlineExceeded. Zero means no limit beyond
+// the caller's own deadline.
+func WithQueryTimeout(d time.Duration) Option {
+	return func(r *eventsRepo) { r.queryTimeout = d }
+}
+
+// WithQueryObserver calls observe after each List and Search with the query type
+// ("list" or "search") and how long it took, rows included.
+func WithQueryObserver(observe func(query string, d time.Duration)) Option {
+	return func(r *eventsRepo) { r.observe = observe }
+}
+
+// NewEventsRepo creates a new events repository.
+func NewEventsRepo(db *sql.DB, opts ...Option) EventsRepo {
+	r := &eventsRepo{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Init creates the events tables and loads the example events.
+func (r *eventsRepo) Init() error {
+	var err error
+
+	r.init.Do(func() {
+		err = r.seed()
+	})
+
+	return err
+}
+
+func (r *eventsRepo) Ping(ctx context.Context) error {
+	var one int
+	err := r.db.QueryRowContext(ctx, getEventQueries()[eventsPing]).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		// An empty table is still readable.
+		return nil
+	}
+	return err
+}
+
+func (r *eventsRepo) List(ctx context.Context) (events []*sports.Event, err error) {
+	ctx, done := r.startQuery(ctx, "list")
+	defer done(&err)
+
+	rows, err := r.db.QueryContext(ctx, getEventQueries()[eventsList])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// startQuery begins a repository call of the given type: it applies the query
+// timeout to ctx and returns done, which the call defers with its error to
+// report a context that ended and time the call.
+func (r *eventsRepo) startQuery(ctx context.Context, kind string) (context.Context, func(*error)) {
+	start := time.Now()
+
+	cancel := context.CancelFunc(func() {})
+	if r.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.queryTimeout)
+	}
+
+	return ctx, func(err *error) {
+		*err = contextError(ctx, *err)
+		cancel()
+		if r.observe != nil {
+			r.observe(kind, time.Since(start))
+		}
+	}
+}
+
+// contextError returns err wrapping ctx's error when ctx has ended. The driver
+// reports a query cut short as merely interrupted, so without this callers
+// could not tell a timeout from any other failure.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ctx.Err(), err)
+}
+
+// Search matches query against the events_fts index and returns the best
+// limit of the matches, ranked by BM25. Name matches count double.
+func (r *eventsRepo) Search(ctx context.Context, query string, limit int) (results []*sports.SearchResult, err error) {
+	if !fts.Enabled {
+		return nil, fts.ErrUnavailable
+	}
+
+	ctx, done := r.startQuery(ctx, "search")
+	defer done(&err)
+
+	match := fts.MatchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := r.db.QueryContext(ctx, getEventQueries()[eventsSearch], match, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			result sports.SearchResult
+			rank   float64
+		)
+
+		event, err := scanEvent(rows, &result.Snippet, &rank)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Event = event
+		result.Score = -rank
+		results = append(results, &result)
+	}
+
+	return results, rows.Err()
+}
+
+// scanEvent scans the event columns of the current row, followed by any extra
+// destinations the query selects after them.
+func scanEvent(rows *sql.Rows, extra ...interface{}) (*sports.Event, error) {
+	var (
+		event        sports.Event
+		participants string
+		start        time.Time
+	)
+
+	dest := append([]interface{}{&event.Id, &event.Name, &event.Location, &participants, &start}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if participants != "" {
+		event.Participants = strings.Split(participants, participantSeparator)
+	}
+	event.AdvertisedStartTime = timestamppb.New(start)
+
+	return &event, nil
+}
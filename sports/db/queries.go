@@ -0,0 +1,40 @@
+package db
+
+const (
+	eventsList   = "list"
+	eventsSearch = "search"
+	eventsPing   = "ping"
+)
+
+func getEventQueries() map[string]string {
+	return map[string]string{
+		eventsList: `
+			SELECT
+				id,
+				name,
+				location,
+				participants,
+				advertised_start_time
+			FROM events
+			ORDER BY advertised_start_time ASC, id ASC
+		`,
+		// bm25() scores better matches lower and weights name matches double;
+		// the limit is -1 for no limit.
+		eventsSearch: `
+			SELECT
+				events.id,
+				events.name,
+				events.location,
+				events.participants,
+				events.advertised_start_time,
+				snippet(events_fts, -1, '<b>', '</b>', '…', 12),
+				bm25(events_fts, 2.0, 1.0, 1.0) AS rank
+			FROM events_fts
+			JOIN events ON events.id = events_fts.rowid
+			WHERE events_fts MATCH ?
+			ORDER BY rank ASC, events.advertised_start_time ASC
+			LIMIT ?
+		`,
+		eventsPing: `SELECT 1 FROM events LIMIT 1`,
+	}
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestRepo creates an events repository over a fresh in-memory SQLite
+// database holding controlled events instead of the example ones.
+func setupTestRepo(t *testing.T, opts ...Option) (*sql.DB, EventsRepo) {
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// Every new connection to :memory: gets its own empty database, so keep
+	// the pool to a single connection.
+	sqldb.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqldb.Close() })
+
+	repo := NewEventsRepo(sqldb, append(opts, WithoutDummyData())...)
+	require.NoError(t, repo.Init())
+
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, event := range [][3]string{
+		{"Eagles vs Lions", "City Oval", "Eagles, Lions"},
+		{"Bears vs Wolves", "Lions Den", "Bears, Wolves"},
+		{"Harbour Cup", "Harbour Park", "Sharks, Rays"},
+		{"Park Run Classic", "Beach Road", "Runners, Walkers"},
+	} {
+		_, err := sqldb.Exec(
+			`INSERT INTO events(id, name, location, participants, advertised_start_time) VALUES (?,?,?,?,?)`,
+			i+1, event[0], event[1], event[2], start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339),
+		)
+		require.NoError(t, err)
+	}
+
+	return sqldb, repo
+}
+
+// resultIDs returns the IDs of the events in results, in order.
+func resultIDs(results []*sports.SearchResult) []int64 {
+	var ids []int64
+	for _, r := range results {
+		ids = append(ids, r.Event.Id)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	_, repo := setupTestRepo(t)
+
+	if !fts.Enabled {
+		_, err := repo.Search(context.Background(), "lions", 10)
+		assert.ErrorIs(t, err, fts.ErrUnavailable)
+		return
+	}
+
+	// Every column is searched, and the last word is matched as a prefix.
+	results, err := repo.Search(context.Background(), "eagles vs li", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, int64(1), results[0].Event.Id)
+		assert.Equal(t, "<b>Eagles</b> <b>vs</b> <b>Lions</b>", results[0].Snippet)
+		assert.Equal(t, []string{"Eagles", "Lions"}, results[0].Event.Participants)
+		assert.Greater(t, results[0].Score, 0.0)
+	}
+
+	results, err = repo.Search(context.Background(), "sharks", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, resultIDs(results), "participants are indexed")
+
+	// A name match counts double, so it beats a location match in a shorter
+	// column.
+	results, err = repo.Search(context.Background(), "park", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 3}, resultIDs(results))
+	if assert.Len(t, results, 2) {
+		assert.Greater(t, results[0].Score, results[1].Score)
+	}
+
+	results, err = repo.Search(context.Background(), "lions", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, resultIDs(results), "the limit keeps the best match")
+
+	results, err = repo.Search(context.Background(), `"*`, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results, "input without terms should match nothing")
+
+	results, err = repo.Search(context.Background(), "cricket", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearch_FollowsWrites(t *testing.T) {
+	if !fts.Enabled {
+		t.Skip("needs -tags sqlite_fts5")
+	}
+
+	sqldb, repo := setupTestRepo(t)
+
+	_, err := sqldb.Exec(`INSERT INTO events(id, name, location, participants, advertised_start_time) VALUES (5, 'Harbour Sprint', 'Marina', 'Sharks, Rays', '2030-01-01T09:00:00Z')`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`UPDATE events SET name = 'Bears vs Eagles', participants = 'Bears, Eagles', advertised_start_time = '2030-01-01T09:00:00Z' WHERE id = 2`)
+	assert.NoError(t, err)
+	_, err = sqldb.Exec(`DELETE FROM events WHERE id = 3`)
+	assert.NoError(t, err)
+
+	results, err := repo.Search(context.Background(), "harbour", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5}, resultIDs(results))
+
+	results, err = repo.Search(context.Background(), "wolves", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results, "the old name is no longer indexed")
+
+	// Equal matches come back soonest first.
+	results, err = repo.Search(context.Background(), "eagles", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, resultIDs(results))
+}
+
+func TestSearch_QueryTimeout(t *testing.T) {
+	if !fts.Enabled {
+		t.Skip("needs -tags sqlite_fts5")
+	}
+
+	var observed []string
+	_, repo := setupTestRepo(t, WithQueryTimeout(time.Nanosecond), WithQueryObserver(func(query string, d time.Duration) {
+		observed = append(observed, query)
+	}))
+
+	_, err := repo.Search(context.Background(), "lions", 10)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"search"}, observed)
+}
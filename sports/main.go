@@ -1,29 +1,202 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"time"
 
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/common/config"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/common/health"
+	"github.com/SylvanSol/Entain_Test/common/logging"
+	"github.com/SylvanSol/Entain_Test/common/metrics"
+	"github.com/SylvanSol/Entain_Test/common/ratelimit"
+	"github.com/SylvanSol/Entain_Test/common/shutdown"
+	"github.com/SylvanSol/Entain_Test/common/tlsconfig"
+	"github.com/SylvanSol/Entain_Test/common/tracing"
+	"github.com/SylvanSol/Entain_Test/common/validate"
+	"github.com/SylvanSol/Entain_Test/sports/db"
 	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
 	"github.com/SylvanSol/Entain_Test/sports/service"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
-	const port = ":9100"
+	cfg := defaultConfig()
+	printConfig, err := config.Load("SPORTS", &cfg, os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			log.Fatalf("failed printing configuration: %v", err)
+		}
+		return
+	}
+
+	slog.SetDefault(logging.New(os.Stderr, cfg.Logging.SlogLevel()))
+
+	if err := run(cfg); err != nil {
+		slog.Error("failed running sports server", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg Config) error {
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return err
+	}
 
-	listener, err := net.Listen("tcp", port)
+	// By default the example events are reloaded on every start, so they live in
+	// memory. Each connection to :memory: is a separate database, hence the pool
+	// of one.
+	sportsDB, err := sql.Open("sqlite3", cfg.DB.DSN)
 	if err != nil {
-		log.Fatalf("failed to listen on %s: %v", port, err)
+		return err
 	}
+	defer sportsDB.Close()
+	sportsDB.SetMaxOpenConns(1)
 
-	grpcServer := grpc.NewServer()
+	reg := metrics.NewRegistry()
+	grpcMetrics := metrics.NewGRPCServer(reg)
+	queryTimer := metrics.NewQueryTimer(reg, "sports")
 
-	sports.RegisterSportsServer(grpcServer, service.NewSportsService())
+	repoOpts := []db.Option{
+		db.WithQueryObserver(queryTimer.Observe),
+		db.WithQueryTimeout(cfg.DB.QueryTimeout),
+	}
+	if cfg.DB.Seed == config.SeedNone {
+		repoOpts = append(repoOpts, db.WithoutDummyData())
+	}
+
+	eventsRepo := db.NewEventsRepo(sportsDB, repoOpts...)
+	if err := eventsRepo.Init(); err != nil {
+		return err
+	}
+	if !fts.Enabled {
+		slog.Warn("built without -tags sqlite_fts5; Search returns UNIMPLEMENTED")
+	}
+
+	reg.MustRegister(
+		collectors.NewDBStatsCollector(sportsDB, "sports"),
+		newUpcomingEventsCollector(eventsRepo),
+	)
+
+	ctx, stop := shutdown.OnSignal()
+	defer stop()
+
+	flushTraces, err := tracing.Setup(ctx, "sports", cfg.Tracing.Exporter, cfg.Tracing.Endpoint)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// ctx is done by the time we get here, so flush on a fresh deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := flushTraces(ctx); err != nil {
+			slog.Error("failed flushing trace spans", "error", err)
+		}
+	}()
+
+	var verifier *auth.Verifier
+	if cfg.Auth.JWKSFile != "" {
+		verifier, err = auth.NewVerifier(cfg.Auth.JWKSFile, cfg.Auth.Issuer, cfg.Auth.Audience)
+		if err != nil {
+			return err
+		}
+	} else {
+		slog.Warn("no JWKS configured; bearer tokens are rejected and only public RPCs can be called")
+	}
+
+	trustedProxies, err := ratelimit.ParsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	limiter := ratelimit.New(cfg.RateLimit.Default(), cfg.RateLimit.Routes)
+	apiKeys := cfg.RateLimit.Keys()
+	go limiter.Run(ctx, time.Minute)
+
+	serverOpts := []grpc.ServerOption{
+		tracing.ServerHandler(),
+		// Log first, so everything after has the request id and refused RPCs
+		// are logged and counted too. Rate limit next, as the cheapest check.
+		// Authorise before validating, so anonymous callers learn nothing
+		// about the RPCs they may not call.
+		grpc.ChainUnaryInterceptor(
+			logging.UnaryServerInterceptor(slog.Default()),
+			grpcMetrics.UnaryServerInterceptor(),
+			ratelimit.UnaryServerInterceptor(limiter, apiKeys, trustedProxies, healthpb.Health_Check_FullMethodName),
+			auth.UnaryServerInterceptor(verifier, service.AuthPolicy()),
+			validate.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(slog.Default()),
+			grpcMetrics.StreamServerInterceptor(),
+			ratelimit.StreamServerInterceptor(limiter, apiKeys, trustedProxies, healthpb.Health_Watch_FullMethodName),
+			auth.StreamServerInterceptor(verifier, service.AuthPolicy()),
+			validate.StreamServerInterceptor(),
+		),
+	}
+	if cfg.TLS.Enabled() {
+		certs, err := tlsconfig.NewReloader(tlsconfig.Files{
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.ClientCAFile,
+		})
+		if err != nil {
+			return err
+		}
+		go certs.Run(ctx, tlsconfig.DefaultReloadInterval)
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certs.ServerConfig())))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+
+	sports.RegisterSportsServer(grpcServer, service.NewSportsService(eventsRepo))
+
+	reporter := health.Register(grpcServer, eventsRepo.Ping, sports.Sports_ServiceDesc.ServiceName)
+	go reporter.Run(ctx, health.DefaultInterval)
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	slog.Info("Sports gRPC server listening", "address", cfg.Listen)
+
+	var metricsServer *http.Server
+	if cfg.Metrics.Listen != "" {
+		metricsServer = metrics.NewServer(cfg.Metrics.Listen, reg)
+		go func() {
+			serveErr <- metricsServer.ListenAndServe()
+		}()
+		slog.Info("Sports metrics listening", "url", "http://"+cfg.Metrics.Listen+"/metrics")
+	}
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
 
-	log.Printf("Sports gRPC server listening on %s", port)
+	// Report NOT_SERVING before draining so probes stop routing traffic here.
+	slog.Info("shutting down, waiting for in-flight RPCs", "timeout", cfg.Timeouts.Shutdown.String())
+	reporter.Shutdown()
 
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	err = shutdown.GRPC(grpcServer, cfg.Timeouts.Shutdown)
+	if metricsServer != nil {
+		err = errors.Join(err, shutdown.HTTP(metricsServer, cfg.Timeouts.Shutdown))
 	}
+	return err
 }
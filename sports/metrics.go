@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/SylvanSol/Entain_Test/sports/db"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// upcomingEventsCollector reports sports_events_upcoming, the number of events
+// that have yet to start. Events are counted when scraped.
+type upcomingEventsCollector struct {
+	repo db.EventsRepo
+	desc *prometheus.Desc
+}
+
+func newUpcomingEventsCollector(repo db.EventsRepo) *upcomingEventsCollector {
+	return &upcomingEventsCollector{
+		repo: repo,
+		desc: prometheus.NewDesc("sports_events_upcoming", "Events that have yet to start.", nil, nil),
+	}
+}
+
+func (c *upcomingEventsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *upcomingEventsCollector) Collect(ch chan<- prometheus.Metric) {
+	events, err := c.repo.List(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	now := time.Now()
+	var n int
+	for _, event := range events {
+		if event.AdvertisedStartTime.AsTime().After(now) {
+			n++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n))
+}
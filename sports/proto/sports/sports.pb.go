@@ -7,6 +7,7 @@
 package sports
 
 import (
+	_ "github.com/SylvanSol/Entain_Test/common/proto/validate"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -28,8 +29,10 @@ type Event struct {
 	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Location            string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
 	AdvertisedStartTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=advertised_start_time,json=advertisedStartTime,proto3" json:"advertised_start_time,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Participants are the teams or players taking part.
+	Participants  []string `protobuf:"bytes,5,rep,name=participants,proto3" json:"participants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Event) Reset() {
@@ -90,6 +93,13 @@ func (x *Event) GetAdvertisedStartTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Event) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
 type ListEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -170,22 +180,198 @@ func (x *ListEventsResponse) GetEvents() []*Event {
 	return nil
 }
 
+// Request for Search call.
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Query is free text matched against event names, locations and
+	// participants. Every word must match, and the last word is matched as a
+	// prefix so partially typed words still find results.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Limit caps the number of results. Defaults to 10.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_sports_sports_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sports_sports_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_sports_sports_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Response to Search call.
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_sports_sports_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sports_sports_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_sports_sports_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SearchResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// An event matching a search.
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Event *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// Snippet is the matching text with each hit wrapped in <b></b>.
+	Snippet string `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	// Score ranks the result; higher is a better match.
+	Score         float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_sports_sports_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_sports_sports_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_sports_sports_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchResult) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
 var File_sports_sports_proto protoreflect.FileDescriptor
 
 const file_sports_sports_proto_rawDesc = "" +
 	"\n" +
-	"\x13sports/sports.proto\x12\x06sports\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x01\n" +
+	"\x13sports/sports.proto\x12\x06sports\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17validate/validate.proto\"\xbb\x01\n" +
 	"\x05Event\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
 	"\blocation\x18\x03 \x01(\tR\blocation\x12N\n" +
-	"\x15advertised_start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x13advertisedStartTime\"\x13\n" +
+	"\x15advertised_start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x13advertisedStartTime\x12\"\n" +
+	"\fparticipants\x18\x05 \x03(\tR\fparticipants\"\x13\n" +
 	"\x11ListEventsRequest\";\n" +
 	"\x12ListEventsResponse\x12%\n" +
-	"\x06events\x18\x01 \x03(\v2\r.sports.EventR\x06events2M\n" +
+	"\x06events\x18\x01 \x03(\v2\r.sports.EventR\x06events\"O\n" +
+	"\rSearchRequest\x12\x1e\n" +
+	"\x05query\x18\x01 \x01(\tB\b\xc2\xf3\x18\x04\b\x018dR\x05query\x12\x1e\n" +
+	"\x05limit\x18\x02 \x01(\x05B\b\xc2\xf3\x18\x04\x10\x00\x182R\x05limit\"@\n" +
+	"\x0eSearchResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.sports.SearchResultR\aresults\"c\n" +
+	"\fSearchResult\x12#\n" +
+	"\x05event\x18\x01 \x01(\v2\r.sports.EventR\x05event\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score2\x86\x01\n" +
 	"\x06Sports\x12C\n" +
 	"\n" +
-	"ListEvents\x12\x19.sports.ListEventsRequest\x1a\x1a.sports.ListEventsResponseB6Z4github.com/SylvanSol/Entain_Test/sports/proto/sportsb\x06proto3"
+	"ListEvents\x12\x19.sports.ListEventsRequest\x1a\x1a.sports.ListEventsResponse\x127\n" +
+	"\x06Search\x12\x15.sports.SearchRequest\x1a\x16.sports.SearchResponseB6Z4github.com/SylvanSol/Entain_Test/sports/proto/sportsb\x06proto3"
 
 var (
 	file_sports_sports_proto_rawDescOnce sync.Once
@@ -199,23 +385,30 @@ func file_sports_sports_proto_rawDescGZIP() []byte {
 	return file_sports_sports_proto_rawDescData
 }
 
-var file_sports_sports_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_sports_sports_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_sports_sports_proto_goTypes = []any{
 	(*Event)(nil),                 // 0: sports.Event
 	(*ListEventsRequest)(nil),     // 1: sports.ListEventsRequest
 	(*ListEventsResponse)(nil),    // 2: sports.ListEventsResponse
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*SearchRequest)(nil),         // 3: sports.SearchRequest
+	(*SearchResponse)(nil),        // 4: sports.SearchResponse
+	(*SearchResult)(nil),          // 5: sports.SearchResult
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
 }
 var file_sports_sports_proto_depIdxs = []int32{
-	3, // 0: sports.Event.advertised_start_time:type_name -> google.protobuf.Timestamp
+	6, // 0: sports.Event.advertised_start_time:type_name -> google.protobuf.Timestamp
 	0, // 1: sports.ListEventsResponse.events:type_name -> sports.Event
-	1, // 2: sports.Sports.ListEvents:input_type -> sports.ListEventsRequest
-	2, // 3: sports.Sports.ListEvents:output_type -> sports.ListEventsResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5, // 2: sports.SearchResponse.results:type_name -> sports.SearchResult
+	0, // 3: sports.SearchResult.event:type_name -> sports.Event
+	1, // 4: sports.Sports.ListEvents:input_type -> sports.ListEventsRequest
+	3, // 5: sports.Sports.Search:input_type -> sports.SearchRequest
+	2, // 6: sports.Sports.ListEvents:output_type -> sports.ListEventsResponse
+	4, // 7: sports.Sports.Search:output_type -> sports.SearchResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_sports_sports_proto_init() }
@@ -229,7 +422,7 @@ func file_sports_sports_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sports_sports_proto_rawDesc), len(file_sports_sports_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/SylvanSol/Entain_Test/common/auth"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// AuthPolicy says who may call each sports RPC. Every sports RPC is currently
+// public.
+func AuthPolicy() auth.Policy {
+	return auth.Policy{
+		sports.Sports_ListEvents_FullMethodName: auth.Public,
+		sports.Sports_Search_FullMethodName:     auth.Public,
+		healthpb.Health_Check_FullMethodName:    auth.Public,
+		healthpb.Health_Watch_FullMethodName:    auth.Public,
+	}
+}
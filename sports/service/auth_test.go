@@ -0,0 +1,26 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestAuthPolicy_CoversEveryRPC(t *testing.T) {
+	policy := AuthPolicy()
+	for _, desc := range []grpc.ServiceDesc{sports.Sports_ServiceDesc, healthpb.Health_ServiceDesc} {
+		for _, method := range desc.Methods {
+			fullMethod := "/" + desc.ServiceName + "/" + method.MethodName
+			assert.Contains(t, policy, fullMethod, "a new RPC needs a rule, or nobody can call it")
+		}
+		for _, stream := range desc.Streams {
+			fullMethod := "/" + desc.ServiceName + "/" + stream.StreamName
+			if assert.Contains(t, policy, fullMethod, "a new RPC needs a rule, or nobody can call it") {
+				assert.Nil(t, policy[fullMethod].ForRequest, "streams are denied to everyone by a rule with ForRequest")
+			}
+		}
+	}
+}
@@ -2,45 +2,66 @@ package service
 
 import (
 	"context"
-	"time"
+	"errors"
 
+	"github.com/SylvanSol/Entain_Test/common/apierr"
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/sports/db"
 	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// defaultSearchLimit is the number of search results returned when the request
+// does not set a limit.
+const defaultSearchLimit = 10
+
 // sportsService implements the SportsServer interface.
 type sportsService struct {
 	sports.UnimplementedSportsServer
+	eventsRepo db.EventsRepo
 }
 
 // NewSportsService returns a new instance of sportsService.
-func NewSportsService() sports.SportsServer {
-	return &sportsService{}
+func NewSportsService(eventsRepo db.EventsRepo) sports.SportsServer {
+	return &sportsService{eventsRepo: eventsRepo}
 }
 
-// ListEvents returns a list of sports matches. (I don't follow sport so I got ChatGPT to tell me some)
+// ListEvents returns every sports match, soonest first.
 func (s *sportsService) ListEvents(ctx context.Context, req *sports.ListEventsRequest) (*sports.ListEventsResponse, error) {
-	now := time.Now()
-	return &sports.ListEventsResponse{
-		Events: []*sports.Event{
-			{
-				Id:                  1,
-				Name:                "Red Hawks vs Blue Titans",
-				Location:            "Thunder Dome",
-				AdvertisedStartTime: timestamppb.New(now.Add(2 * time.Hour)),
-			},
-			{
-				Id:                  2,
-				Name:                "Iron Bears vs Golden Foxes",
-				Location:            "Victory Stadium",
-				AdvertisedStartTime: timestamppb.New(now.Add(4 * time.Hour)),
-			},
-			{
-				Id:                  3,
-				Name:                "Night Wolves vs Storm Kings",
-				Location:            "Arena Eclipse",
-				AdvertisedStartTime: timestamppb.New(now.Add(6 * time.Hour)),
-			},
-		},
-	}, nil
+	events, err := s.eventsRepo.List(ctx)
+	if err != nil {
+		return nil, repoError(ctx, "list events", err)
+	}
+
+	return &sports.ListEventsResponse{Events: events}, nil
+}
+
+// Search returns the matches whose name, location or participants match the
+// query, best match first.
+func (s *sportsService) Search(ctx context.Context, req *sports.SearchRequest) (*sports.SearchResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	results, err := s.eventsRepo.Search(ctx, req.Query, limit)
+	if errors.Is(err, fts.ErrUnavailable) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return nil, repoError(ctx, "search events", err)
+	}
+
+	return &sports.SearchResponse{Results: results}, nil
+}
+
+// repoError maps a repository error onto a gRPC status: Canceled or
+// DeadlineExceeded when the caller gave up or the query ran out of time, and
+// Internal otherwise.
+func repoError(ctx context.Context, op string, err error) error {
+	if err := apierr.FromContext(op, err); err != nil {
+		return err
+	}
+	return apierr.Internal(ctx, op, err)
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SylvanSol/Entain_Test/common/fts"
+	"github.com/SylvanSol/Entain_Test/sports/db"
+	"github.com/SylvanSol/Entain_Test/sports/proto/sports"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestService returns a sportsService backed by a fresh in-memory database
+// holding the example events.
+func newTestService(t *testing.T) sports.SportsServer {
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite memory db: %v", err)
+	}
+	sqldb.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqldb.Close() })
+
+	repo := db.NewEventsRepo(sqldb)
+	if err := repo.Init(); err != nil {
+		t.Fatalf("failed to initialise events: %v", err)
+	}
+	return NewSportsService(repo)
+}
+
+func TestListEvents_ReturnsMockEvents(t *testing.T) {
+	svc := newTestService(t)
+
+	resp, err := svc.ListEvents(context.Background(), &sports.ListEventsRequest{})
+	assert.NoError(t, err)
+	if !assert.Len(t, resp.Events, 3) {
+		return
+	}
+
+	assert.Equal(t, int64(1), resp.Events[0].Id)
+	assert.Equal(t, "Red Hawks vs Blue Titans", resp.Events[0].Name)
+	assert.Equal(t, "Thunder Dome", resp.Events[0].Location)
+
+	// Events are returned in start time order.
+	for i := 1; i < len(resp.Events); i++ {
+		assert.True(t, resp.Events[i-1].AdvertisedStartTime.AsTime().Before(resp.Events[i].AdvertisedStartTime.AsTime()))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	svc := newTestService(t)
+
+	if !fts.Enabled {
+		_, err := svc.Search(context.Background(), &sports.SearchRequest{Query: "red"})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+		return
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []int64
+	}{
+		{name: "by name prefix", query: "red ha", wantIDs: []int64{1}},
+		{name: "by location", query: "victory", wantIDs: []int64{2}},
+		{name: "by participant", query: "storm kings", wantIDs: []int64{3}},
+		{name: "across events", query: "s", wantIDs: []int64{3, 2}},
+		{name: "no match", query: "cricket", wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Search(context.Background(), &sports.SearchRequest{Query: tt.query})
+			assert.NoError(t, err)
+
+			var ids []int64
+			for _, r := range resp.Results {
+				ids = append(ids, r.Event.Id)
+				assert.Contains(t, r.Snippet, "<b>")
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}